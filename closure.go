@@ -0,0 +1,75 @@
+package daggo
+
+import (
+	"sort"
+)
+
+// TransitiveClosure returns a new DAG that every vertice connects to all vertices reachable from it.
+// The weight of a new connecting is the total weight of the shortest path, the existing connecting is kept.
+func (d *Graph[V, W]) TransitiveClosure() *Graph[V, W] {
+	d.rlock()
+	defer d.runlock()
+
+	nd := NewGraph[V, W]()
+	for k, b := range d.blocks {
+		nd.blocks[k] = b.clone()
+	}
+	for k := range d.blocks {
+		b := nd.blocks[k]
+		for kk, s := range d.relax(k, true, false) {
+			if _, ok := b.next[kk]; ok || kk == k {
+				continue
+			}
+			b.next[kk] = s.weight
+			nd.blocks[kk].prev[k] = s.weight
+		}
+	}
+	return nd
+}
+
+// TransitiveReduction returns a new DAG with the fewest edges that represents the same reachability relation.
+// Every edge of it is an edge of the DAG with the same weight.
+func (d *Graph[V, W]) TransitiveReduction() *Graph[V, W] {
+	d.rlock()
+	defer d.runlock()
+
+	nd := NewGraph[V, W]()
+	for k, b := range d.blocks {
+		nd.blocks[k] = &block[V, W]{
+			vertice: b.vertice,
+			prev:    make(map[string]W),
+			next:    make(map[string]W),
+		}
+	}
+
+	keys := d.topoKeys(nil)
+	pos := make(map[string]int, len(keys))
+	for i, k := range keys {
+		pos[k] = i
+	}
+	// desc[i] is the descendants of keys[i]
+	desc := make([]bitset, len(keys))
+	for i := len(keys) - 1; i >= 0; i-- {
+		k := keys[i]
+		b := d.blocks[k]
+		next := make([]string, 0, len(b.next))
+		for kk := range b.next {
+			next = append(next, kk)
+		}
+		// a vertice can only be reached from the vertices before it
+		sort.Slice(next, func(i, j int) bool { return pos[next[i]] < pos[next[j]] })
+		reach := bitset{}
+		for _, kk := range next {
+			j := pos[kk]
+			if reach.has(j) {
+				continue
+			}
+			nd.blocks[k].next[kk] = b.next[kk]
+			nd.blocks[kk].prev[k] = b.next[kk]
+			reach.set(j)
+			reach.or(desc[j])
+		}
+		desc[i] = reach
+	}
+	return nd
+}
@@ -0,0 +1,82 @@
+package daggo_test
+
+import (
+	"testing"
+
+	daggo "github.com/open-trust/dag-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClosure(t *testing.T) {
+	t.Run("DAG.TransitiveClosure", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		assert.Nil(d.AddEdge(V("a"), V("b"), 1))
+		assert.Nil(d.AddEdge(V("b"), V("c"), 2))
+		assert.Nil(d.AddEdge(V("a"), V("c"), 5))
+		assert.Nil(d.AddEdge(V("c"), V("d"), 1))
+		assert.Nil(d.AddEdge(V("a"), V("d"), 9))
+		// another component
+		assert.Nil(d.AddEdge(V("x"), V("y"), 1))
+		assert.Nil(d.AddEdge(V("y"), V("z"), 1))
+		assert.Nil(d.AddEdge(V("w"), V("z"), 1))
+		assert.Nil(d.AddVertice(V("o")))
+
+		x := d.Clone()
+		c := d.TransitiveClosure()
+		assert.True(x.Equal(d))
+
+		y := daggo.New()
+		assert.Nil(y.AddEdge(V("a"), V("b"), 1))
+		assert.Nil(y.AddEdge(V("b"), V("c"), 2))
+		assert.Nil(y.AddEdge(V("a"), V("c"), 5))
+		assert.Nil(y.AddEdge(V("c"), V("d"), 1))
+		assert.Nil(y.AddEdge(V("a"), V("d"), 9))
+		assert.Nil(y.AddEdge(V("b"), V("d"), 3))
+		assert.Nil(y.AddEdge(V("x"), V("y"), 1))
+		assert.Nil(y.AddEdge(V("y"), V("z"), 1))
+		assert.Nil(y.AddEdge(V("x"), V("z"), 2))
+		assert.Nil(y.AddEdge(V("w"), V("z"), 1))
+		assert.Nil(y.AddVertice(V("o")))
+		assert.True(y.Equal(c))
+
+		assert.True(c.Equal(c.TransitiveClosure()))
+		assert.Equal(0, daggo.New().TransitiveClosure().Len())
+	})
+
+	t.Run("DAG.TransitiveReduction", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		assert.Nil(d.AddEdge(V("a"), V("b"), 1))
+		assert.Nil(d.AddEdge(V("b"), V("c"), 2))
+		assert.Nil(d.AddEdge(V("a"), V("c"), 5))
+		assert.Nil(d.AddEdge(V("c"), V("d"), 1))
+		assert.Nil(d.AddEdge(V("a"), V("d"), 9))
+		// another component
+		assert.Nil(d.AddEdge(V("x"), V("y"), 1))
+		assert.Nil(d.AddEdge(V("y"), V("z"), 1))
+		assert.Nil(d.AddEdge(V("w"), V("z"), 1))
+		assert.Nil(d.AddVertice(V("o")))
+
+		x := d.Clone()
+		r := d.TransitiveReduction()
+		assert.True(x.Equal(d))
+
+		y := daggo.New()
+		assert.Nil(y.AddEdge(V("a"), V("b"), 1))
+		assert.Nil(y.AddEdge(V("b"), V("c"), 2))
+		assert.Nil(y.AddEdge(V("c"), V("d"), 1))
+		assert.Nil(y.AddEdge(V("x"), V("y"), 1))
+		assert.Nil(y.AddEdge(V("y"), V("z"), 1))
+		assert.Nil(y.AddEdge(V("w"), V("z"), 1))
+		assert.Nil(y.AddVertice(V("o")))
+		assert.True(y.Equal(r))
+
+		assert.True(r.Equal(r.TransitiveReduction()))
+		assert.True(r.Equal(d.TransitiveClosure().TransitiveReduction()))
+		assert.True(d.ReduceDAG(V("a"), V("d")).Equal(r.CloseDAG(V("a"), V("d"))))
+		assert.Equal(0, daggo.New().TransitiveReduction().Len())
+	})
+}
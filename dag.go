@@ -3,6 +3,7 @@ package daggo
 import (
 	"fmt"
 	"sort"
+	"sync"
 )
 
 // Vertice is a vertice formed DAG.
@@ -15,23 +16,37 @@ func verticeUID(v Vertice) string {
 	return fmt.Sprintf("%s:%s", v.Type(), v.ID())
 }
 
+func isNilVertice[V Vertice](v V) bool {
+	return Vertice(v) == nil
+}
+
+// Weight is the constraint of edge weights, any ordered numeric type such as int, float64 or time.Duration.
+type Weight interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// List is a slice of typed vertices.
+type List[V Vertice] []V
+
 // Vertices is a slice of vertices.
-type Vertices []Vertice
+type Vertices = List[Vertice]
 
-func (s Vertices) Len() int { return len(s) }
-func (s Vertices) Less(i, j int) bool {
+func (s List[V]) Len() int { return len(s) }
+func (s List[V]) Less(i, j int) bool {
 	return verticeUID(s[i]) < verticeUID(s[j])
 }
-func (s Vertices) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s List[V]) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
 
 // Sort returns a slice of vertices in increasing order by vertice ID.
-func (s Vertices) Sort() Vertices {
+func (s List[V]) Sort() List[V] {
 	sort.Stable(s)
 	return s
 }
 
 // IDs returns a slice of vertices' IDs
-func (s Vertices) IDs() []string {
+func (s List[V]) IDs() []string {
 	res := make([]string, len(s))
 	for i, v := range s {
 		res[i] = v.ID()
@@ -40,8 +55,8 @@ func (s Vertices) IDs() []string {
 }
 
 // Filter ...
-func (s Vertices) Filter(fn func(v Vertice) bool) Vertices {
-	res := make([]Vertice, 0, len(s)/2)
+func (s List[V]) Filter(fn func(v V) bool) List[V] {
+	res := make([]V, 0, len(s)/2)
 	for _, v := range s {
 		if fn(v) {
 			res = append(res, v)
@@ -50,71 +65,138 @@ func (s Vertices) Filter(fn func(v Vertice) bool) Vertices {
 	return res
 }
 
-// DAG is a directed acyclic graph.
-type DAG struct {
-	blocks map[string]*block
+// Graph is a directed acyclic graph with typed vertices V and typed edge weights W.
+// A Graph created by NewGraph is not safe for concurrent use, use NewSyncGraph instead.
+type Graph[V Vertice, W Weight] struct {
+	mu     *sync.RWMutex
+	blocks map[string]*block[V, W]
+	index  *reachIndex    // the attached reachability index, see ReachIndex
+	ord    map[string]int // a topological order maintained by AddEdge, nil if not computed
+	minOrd int
+	maxOrd int
 }
 
-// JSON ...
-type JSON struct {
-	Vertices []Vertice                 `json:"vertices"`
-	Edges    map[string]map[string]int `json:"edges"`
+// DAG is a directed acyclic graph with Vertice vertices and int edge weights.
+// A DAG created by New is not safe for concurrent use, use NewSync instead.
+type DAG = Graph[Vertice, int]
+
+// GraphJSON ...
+type GraphJSON[V Vertice, W Weight] struct {
+	Vertices []V                     `json:"vertices"`
+	Edges    map[string]map[string]W `json:"edges"`
 }
 
+// JSON ...
+type JSON = GraphJSON[Vertice, int]
+
 // New returns a new DAG.
 func New() *DAG {
-	return &DAG{
-		blocks: make(map[string]*block),
+	return NewGraph[Vertice, int]()
+}
+
+// NewSync returns a new DAG that is safe for concurrent use by multiple goroutines.
+// Mutations run under a write lock and queries run under a read lock.
+func NewSync() *DAG {
+	return NewSyncGraph[Vertice, int]()
+}
+
+// NewGraph returns a new Graph.
+func NewGraph[V Vertice, W Weight]() *Graph[V, W] {
+	return &Graph[V, W]{
+		blocks: make(map[string]*block[V, W]),
 	}
 }
 
-// FromJSON returns a DAG from JSON structured data, return nil if some data invalid.
-func FromJSON(j *JSON) *DAG {
-	dag := &DAG{
-		blocks: make(map[string]*block),
+// NewSyncGraph returns a new Graph that is safe for concurrent use by multiple goroutines.
+// Mutations run under a write lock and queries run under a read lock.
+func NewSyncGraph[V Vertice, W Weight]() *Graph[V, W] {
+	return &Graph[V, W]{
+		mu:     new(sync.RWMutex),
+		blocks: make(map[string]*block[V, W]),
 	}
+}
+
+func (d *Graph[V, W]) lock() {
+	if d.mu != nil {
+		d.mu.Lock()
+	}
+}
+
+func (d *Graph[V, W]) unlock() {
+	if d.mu != nil {
+		d.mu.Unlock()
+	}
+}
+
+func (d *Graph[V, W]) rlock() {
+	if d.mu != nil {
+		d.mu.RLock()
+	}
+}
+
+func (d *Graph[V, W]) runlock() {
+	if d.mu != nil {
+		d.mu.RUnlock()
+	}
+}
+
+// FromJSON returns a DAG from JSON structured data, returns error if some data invalid.
+func FromJSON(j *JSON) (*DAG, error) {
+	return GraphFromJSON(j)
+}
+
+// GraphFromJSON returns a Graph from JSON structured data, returns error if some data invalid.
+func GraphFromJSON[V Vertice, W Weight](j *GraphJSON[V, W]) (*Graph[V, W], error) {
+	dag := NewGraph[V, W]()
 	for _, v := range j.Vertices {
+		if isNilVertice(v) || v.ID() == "" {
+			return nil, &ErrInvalidVertice{Vertice: v}
+		}
 		k := verticeUID(v)
 		if _, ok := dag.blocks[k]; ok {
-			return nil
+			return nil, &ErrDuplicateVertice{Vertice: v}
 		}
-		dag.blocks[k] = &block{
+		dag.blocks[k] = &block[V, W]{
 			vertice: v,
-			prev:    make(map[string]int),
-			next:    make(map[string]int),
+			prev:    make(map[string]W),
+			next:    make(map[string]W),
 		}
 	}
 	for k, v := range j.Edges {
 		startBlock, ok := dag.blocks[k]
 		if !ok {
-			return nil
+			return nil, &ErrUnknownVertice{UID: k}
 		}
 		for kk, w := range v {
 			endBlock, ok := dag.blocks[kk]
 			if !ok {
-				return nil
+				return nil, &ErrUnknownVertice{UID: kk}
 			}
-			if dag.isReachable(endBlock, k) {
-				return nil
+			if startBlock == endBlock {
+				return nil, &ErrSelfLoop{Vertice: startBlock.vertice}
 			}
 			startBlock.next[kk] = w
 			endBlock.prev[k] = w
 		}
 	}
-	return dag
+	// validate once after all the edges are added
+	if cycle := dag.cycleKeys(); cycle != nil {
+		return nil, dag.cycleError(cycle)
+	}
+	return dag, nil
 }
 
-type block struct {
-	vertice Vertice
-	prev    map[string]int
-	next    map[string]int
+type block[V Vertice, W Weight] struct {
+	vertice V
+	prev    map[string]W
+	next    map[string]W
 }
 
-func (b *block) clone() *block {
-	x := &block{
+func (b *block[V, W]) clone() *block[V, W] {
+	x := &block[V, W]{
 		vertice: b.vertice,
-		prev:    make(map[string]int),
-		next:    make(map[string]int),
+		prev:    make(map[string]W),
+		next:    make(map[string]W),
 	}
 	for k, w := range b.prev {
 		x.prev[k] = w
@@ -126,22 +208,32 @@ func (b *block) clone() *block {
 }
 
 // Len returns vertices count in the DAG.
-func (d *DAG) Len() int {
+func (d *Graph[V, W]) Len() int {
+	d.rlock()
+	defer d.runlock()
+
 	return len(d.blocks)
 }
 
 // GetVertice returns a vertice with type and id in the DAG, returns nil if not found.
-func (d *DAG) GetVertice(ty, id string) Vertice {
+func (d *Graph[V, W]) GetVertice(ty, id string) V {
+	d.rlock()
+	defer d.runlock()
+
 	block, ok := d.blocks[fmt.Sprintf("%s:%s", ty, id)]
 	if ok {
 		return block.vertice
 	}
-	return nil
+	var zero V
+	return zero
 }
 
 // Vertices returns a type of vertices in the DAG, returns all if type is empty.
-func (d *DAG) Vertices(ty string) Vertices {
-	res := make([]Vertice, 0, len(d.blocks)/2)
+func (d *Graph[V, W]) Vertices(ty string) List[V] {
+	d.rlock()
+	defer d.runlock()
+
+	res := make([]V, 0, len(d.blocks)/2)
 	for _, b := range d.blocks {
 		if ty == "" || ty == b.vertice.Type() {
 			res = append(res, b.vertice)
@@ -151,8 +243,12 @@ func (d *DAG) Vertices(ty string) Vertices {
 }
 
 // StartingVertices returns starting vertices in the DAG that have no other vertices connected to them.
-func (d *DAG) StartingVertices() Vertices {
-	res := make([]Vertice, 0)
+// An isolated vertice without any connecting is both a starting vertice and an ending vertice.
+func (d *Graph[V, W]) StartingVertices() List[V] {
+	d.rlock()
+	defer d.runlock()
+
+	res := make([]V, 0)
 	for _, b := range d.blocks {
 		if len(b.prev) == 0 {
 			res = append(res, b.vertice)
@@ -162,10 +258,14 @@ func (d *DAG) StartingVertices() Vertices {
 }
 
 // EndingVertices returns ending vertices in the DAG that don't connected to any other vertices.
-func (d *DAG) EndingVertices() Vertices {
-	res := make([]Vertice, 0)
+// An isolated vertice without any connecting is both a starting vertice and an ending vertice.
+func (d *Graph[V, W]) EndingVertices() List[V] {
+	d.rlock()
+	defer d.runlock()
+
+	res := make([]V, 0)
 	for _, b := range d.blocks {
-		if len(b.next) == 0 && len(b.prev) != 0 {
+		if len(b.next) == 0 {
 			res = append(res, b.vertice)
 		}
 	}
@@ -173,9 +273,12 @@ func (d *DAG) EndingVertices() Vertices {
 }
 
 // ToVertices returns vertices in the DAG that the vertice v connected to them.
-func (d *DAG) ToVertices(v Vertice) Vertices {
-	res := make([]Vertice, 0)
-	if v == nil {
+func (d *Graph[V, W]) ToVertices(v V) List[V] {
+	d.rlock()
+	defer d.runlock()
+
+	res := make([]V, 0)
+	if isNilVertice(v) {
 		return res
 	}
 
@@ -191,9 +294,12 @@ func (d *DAG) ToVertices(v Vertice) Vertices {
 }
 
 // FromVertices returns vertices in the DAG that connected to the vertice v.
-func (d *DAG) FromVertices(v Vertice) Vertices {
-	res := make([]Vertice, 0)
-	if v == nil {
+func (d *Graph[V, W]) FromVertices(v V) List[V] {
+	d.rlock()
+	defer d.runlock()
+
+	res := make([]V, 0)
+	if isNilVertice(v) {
 		return res
 	}
 
@@ -208,8 +314,96 @@ func (d *DAG) FromVertices(v Vertice) Vertices {
 	return res
 }
 
+// Descendants returns vertices in the DAG that reachable from the vertice v within maxDepth edges,
+// nearer vertices first. A maxDepth <= 0 means unlimited, and the vertices can be filtered by types.
+func (d *Graph[V, W]) Descendants(v V, maxDepth int, types ...string) List[V] {
+	d.rlock()
+	defer d.runlock()
+
+	return d.traverse(v, maxDepth, types, false)
+}
+
+// Ancestors returns vertices in the DAG that can reach the vertice v within maxDepth edges,
+// nearer vertices first. A maxDepth <= 0 means unlimited, and the vertices can be filtered by types.
+func (d *Graph[V, W]) Ancestors(v V, maxDepth int, types ...string) List[V] {
+	d.rlock()
+	defer d.runlock()
+
+	return d.traverse(v, maxDepth, types, true)
+}
+
+// IsReachable returns whether there is a path from the vertice a to b.
+func (d *Graph[V, W]) IsReachable(a, b V) bool {
+	d.rlock()
+	defer d.runlock()
+
+	if isNilVertice(a) || isNilVertice(b) {
+		return false
+	}
+	x, ok := d.blocks[verticeUID(a)]
+	if !ok {
+		return false
+	}
+	return d.reachable(x, verticeUID(b))
+}
+
+// traverse walks the DAG from the vertice v breadth-first, level by level in UID order.
+func (d *Graph[V, W]) traverse(v V, maxDepth int, types []string, reverse bool) List[V] {
+	res := make([]V, 0)
+	if isNilVertice(v) {
+		return res
+	}
+	k := verticeUID(v)
+	if _, ok := d.blocks[k]; !ok {
+		return res
+	}
+
+	visited := map[string]bool{k: true}
+	level := []string{k}
+	for depth := 1; len(level) > 0 && (maxDepth <= 0 || depth <= maxDepth); depth++ {
+		next := make([]string, 0)
+		for _, k := range level {
+			edges := d.blocks[k].next
+			if reverse {
+				edges = d.blocks[k].prev
+			}
+			for kk := range edges {
+				if !visited[kk] {
+					visited[kk] = true
+					next = append(next, kk)
+				}
+			}
+		}
+		sort.Strings(next)
+		for _, k := range next {
+			if b := d.blocks[k]; len(types) == 0 || containsString(types, b.vertice.Type()) {
+				res = append(res, b.vertice)
+			}
+		}
+		level = next
+	}
+	return res
+}
+
+func containsString(ss []string, s string) bool {
+	for _, x := range ss {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
 // Equal asserts that two DAG are equal.
-func (d *DAG) Equal(a *DAG) bool {
+func (d *Graph[V, W]) Equal(a *Graph[V, W]) bool {
+	if d == a {
+		return true
+	}
+	// take a snapshot so that the two DAG are never locked at the same time.
+	a = a.Clone()
+	d.rlock()
+	defer d.runlock()
+
 	if len(d.blocks) != len(a.blocks) {
 		return false
 	}
@@ -234,30 +428,23 @@ func (d *DAG) Equal(a *DAG) bool {
 	return true
 }
 
-// Merge merge two DAG into one, return error if cyclic graph will come into being
-func (d *DAG) Merge(a *DAG) error {
-	for k, x := range a.blocks {
-		b, ok := d.blocks[k]
-		if !ok {
-			d.blocks[k] = x.clone()
-		} else {
-			for kk, w := range x.next {
-				b.next[kk] = w
-			}
-			for kk, w := range x.prev {
-				b.prev[kk] = w
-			}
-			if d.isReachable(b, k) {
-				return fmt.Errorf("cyclic graph will come into being")
-			}
-		}
-	}
-	return nil
+// Merge merge two DAG into one, return error if cyclic graph will come into being.
+// The merge is atomic, the DAG is unchanged if error returned.
+// The weight of a connecting that exists in both DAG is overwritten by the other DAG.
+func (d *Graph[V, W]) Merge(a *Graph[V, W]) error {
+	_, err := d.MergeWith(a, MergeOverwrite)
+	return err
 }
 
-// Clone returns a clone DAG.
-func (d *DAG) Clone() *DAG {
-	ng := New()
+// Clone returns a clone DAG, it is safe for concurrent use if the DAG is.
+func (d *Graph[V, W]) Clone() *Graph[V, W] {
+	d.rlock()
+	defer d.runlock()
+
+	ng := NewGraph[V, W]()
+	if d.mu != nil {
+		ng.mu = new(sync.RWMutex)
+	}
 	for k, x := range d.blocks {
 		ng.blocks[k] = x.clone()
 	}
@@ -265,14 +452,17 @@ func (d *DAG) Clone() *DAG {
 }
 
 // JSON ...
-func (d *DAG) JSON() *JSON {
-	j := &JSON{
-		Vertices: make([]Vertice, 0, len(d.blocks)),
-		Edges:    make(map[string]map[string]int),
+func (d *Graph[V, W]) JSON() *GraphJSON[V, W] {
+	d.rlock()
+	defer d.runlock()
+
+	j := &GraphJSON[V, W]{
+		Vertices: make([]V, 0, len(d.blocks)),
+		Edges:    make(map[string]map[string]W),
 	}
 	for k, b := range d.blocks {
 		j.Vertices = append(j.Vertices, b.vertice)
-		j.Edges[k] = make(map[string]int)
+		j.Edges[k] = make(map[string]W)
 		for kk, w := range b.next {
 			j.Edges[k][kk] = w
 		}
@@ -280,56 +470,88 @@ func (d *DAG) JSON() *JSON {
 	return j
 }
 
+// AddVertice adds a vertice without any connecting into the DAG.
+// the vertice should not be nil and not exist in the DAG.
+func (d *Graph[V, W]) AddVertice(v V) error {
+	d.lock()
+	defer d.unlock()
+
+	if isNilVertice(v) || v.ID() == "" {
+		return &ErrInvalidVertice{Vertice: v}
+	}
+
+	k := verticeUID(v)
+	if _, ok := d.blocks[k]; ok {
+		return &ErrDuplicateVertice{Vertice: v}
+	}
+	d.blocks[k] = &block[V, W]{
+		vertice: v,
+		prev:    make(map[string]W),
+		next:    make(map[string]W),
+	}
+	if d.index != nil {
+		d.index.id(k)
+	}
+	return nil
+}
+
 // AddEdge adds a connecting pairs of vertices into the DAG.
 // the vertices should not be nil, not be equal, and not form a cyclic graph.
 // the method can be called multiple times.
-func (d *DAG) AddEdge(start, end Vertice, weight int) error {
-	if start == nil || start.ID() == "" {
-		return fmt.Errorf("invalid starting vertice: %#v", start)
+func (d *Graph[V, W]) AddEdge(start, end V, weight W) error {
+	d.lock()
+	defer d.unlock()
+
+	if isNilVertice(start) || start.ID() == "" {
+		return &ErrInvalidVertice{Vertice: start}
 	}
-	if end == nil || end.ID() == "" {
-		return fmt.Errorf("invalid ending vertice: %#v", end)
+	if isNilVertice(end) || end.ID() == "" {
+		return &ErrInvalidVertice{Vertice: end}
 	}
 
 	startID := verticeUID(start)
 	endID := verticeUID(end)
 	if startID == endID {
-		return fmt.Errorf("starting vertice is ending vertice: %s", startID)
+		return &ErrSelfLoop{Vertice: start}
 	}
 
 	startBlock, ok1 := d.blocks[startID]
 	if !ok1 {
-		startBlock = &block{
+		startBlock = &block[V, W]{
 			vertice: start,
-			prev:    make(map[string]int),
-			next:    make(map[string]int),
+			prev:    make(map[string]W),
+			next:    make(map[string]W),
 		}
 		d.blocks[startID] = startBlock
 	}
 
 	endBlock, ok2 := d.blocks[endID]
 	if !ok2 {
-		endBlock = &block{
+		endBlock = &block[V, W]{
 			vertice: end,
-			prev:    make(map[string]int),
-			next:    make(map[string]int),
+			prev:    make(map[string]W),
+			next:    make(map[string]W),
 		}
 		d.blocks[endID] = endBlock
 	}
 
-	if ok1 && ok2 {
-		if d.isReachable(endBlock, startID) {
-			return fmt.Errorf("cyclic graph will come into being")
-		}
+	if !d.orderEdge(startID, endID) {
+		return d.cycleError(append([]string{startID}, d.reachPath(endBlock, startID)...))
 	}
 	startBlock.next[endID] = weight
 	endBlock.prev[startID] = weight
+	if d.index != nil {
+		d.index.addEdge(startID, endID)
+	}
 	return nil
 }
 
 // RemoveEdge remove the direct connecting in the vertices pair.
-func (d *DAG) RemoveEdge(start, end Vertice) {
-	if start == nil || end == nil {
+func (d *Graph[V, W]) RemoveEdge(start, end V) {
+	d.lock()
+	defer d.unlock()
+
+	if isNilVertice(start) || isNilVertice(end) {
 		return
 	}
 
@@ -345,110 +567,250 @@ func (d *DAG) RemoveEdge(start, end Vertice) {
 		return
 	}
 
+	if _, ok := startBlock.next[endID]; !ok {
+		return
+	}
 	delete(startBlock.next, endID)
 	delete(endBlock.prev, startID)
+	if d.index != nil {
+		d.updateReachIndex(d.reachOrder(startID, true))
+	}
 }
 
-// ReachDAG returns a new sub DAG with the most edges that starting vertice may reach to.
-func (d *DAG) ReachDAG(start Vertice) *DAG {
-	nd := New()
-	if start == nil {
-		return nd
+// RemoveVertice remove the vertice and all the connecting of it.
+func (d *Graph[V, W]) RemoveVertice(v V) {
+	d.lock()
+	defer d.unlock()
+
+	if isNilVertice(v) {
+		return
+	}
+	k := verticeUID(v)
+	if _, ok := d.blocks[k]; !ok {
+		return
+	}
+	ancestors := d.reachOrder(k, true)
+	d.removeBlock(k)
+	if d.index != nil {
+		d.updateReachIndex(ancestors[:len(ancestors)-1])
 	}
+}
 
-	startBlock, ok := d.blocks[verticeUID(start)]
+// ContractVertice remove the vertice and connect its from vertices to its to vertices,
+// so the reachability relation of other vertices is kept.
+// The weight of a new connecting is combined from the two connecting weights by the combine function,
+// and summed if combine is nil. The existing direct connecting is kept.
+// The combine is called with the write lock held, it should not call any method of the DAG,
+// otherwise it may deadlock a DAG created by NewSync.
+func (d *Graph[V, W]) ContractVertice(v V, combine func(prev, next W) W) {
+	d.lock()
+	defer d.unlock()
+
+	if isNilVertice(v) {
+		return
+	}
+	k := verticeUID(v)
+	b, ok := d.blocks[k]
 	if !ok {
-		return nd
+		return
+	}
+	if combine == nil {
+		combine = func(prev, next W) W { return prev + next }
 	}
-	var iterator func(n *block)
-	iterator = func(n *block) {
-		for k, w := range n.next {
-			b := d.blocks[k]
-			nd.AddEdge(n.vertice, b.vertice, w)
-			iterator(b)
+
+	for pk, pw := range b.prev {
+		pb := d.blocks[pk]
+		for nk, nw := range b.next {
+			if _, ok := pb.next[nk]; ok {
+				continue
+			}
+			w := combine(pw, nw)
+			pb.next[nk] = w
+			d.blocks[nk].prev[pk] = w
 		}
 	}
-	iterator(startBlock)
-	return nd
+	d.removeBlock(k)
 }
 
-// CloseDAG returns a new transitive closure DAG with the most edges that represents the same reachability relation.
-func (d *DAG) CloseDAG(start, end Vertice) *DAG {
-	nd := New()
-	if start == nil {
-		return nd
-	}
+// setBlocks replaces the blocks of the DAG after bulk mutations.
+func (d *Graph[V, W]) setBlocks(blocks map[string]*block[V, W]) {
+	d.blocks = blocks
+	d.ord = nil
+	d.reindex()
+}
 
-	startBlock, ok := d.blocks[verticeUID(start)]
+func (d *Graph[V, W]) removeBlock(k string) {
+	b, ok := d.blocks[k]
 	if !ok {
-		return nd
+		return
+	}
+	for kk := range b.prev {
+		delete(d.blocks[kk].next, k)
+	}
+	for kk := range b.next {
+		delete(d.blocks[kk].prev, k)
+	}
+	delete(d.blocks, k)
+	if d.index != nil {
+		delete(d.index.ids, k)
 	}
-	if end == nil {
-		return nd
+	if d.ord != nil {
+		delete(d.ord, k)
 	}
+}
 
-	endBlock, ok := d.blocks[verticeUID(end)]
-	if !ok {
-		return nd
+// ReachDAG returns a new sub DAG with the most edges that starting vertice may reach to.
+func (d *Graph[V, W]) ReachDAG(start V) *Graph[V, W] {
+	d.rlock()
+	defer d.runlock()
+
+	if isNilVertice(start) {
+		return NewGraph[V, W]()
 	}
-	if startBlock == endBlock {
-		return nd
+	startBlock, ok := d.blocks[verticeUID(start)]
+	if !ok || len(startBlock.next) == 0 {
+		return NewGraph[V, W]()
 	}
+	return d.induced(d.reachSet(d.existingKeys(List[V]{start}), false))
+}
 
-	var iterator func(n *block) bool
-	iterator = func(n *block) bool {
-		ok := false
-		for k, w := range n.next {
-			b := d.blocks[k]
-			if b == endBlock || iterator(b) {
-				nd.AddEdge(n.vertice, b.vertice, w)
-				ok = true
-			}
+// ReachDAGFrom returns a new sub DAG induced by the starts and the vertices that any of them may reach to.
+func (d *Graph[V, W]) ReachDAGFrom(starts List[V]) *Graph[V, W] {
+	d.rlock()
+	defer d.runlock()
+
+	return d.induced(d.reachSet(d.existingKeys(starts), false))
+}
+
+// AncestorDAG returns a new sub DAG induced by the ends and the vertices that may reach to any of them.
+func (d *Graph[V, W]) AncestorDAG(ends List[V]) *Graph[V, W] {
+	d.rlock()
+	defer d.runlock()
+
+	return d.induced(d.reachSet(d.existingKeys(ends), true))
+}
+
+// BetweenDAG returns a new sub DAG induced by the vertices lying on any path from the starts to the ends,
+// the paths include the vertices both in the starts and the ends.
+func (d *Graph[V, W]) BetweenDAG(starts, ends List[V]) *Graph[V, W] {
+	d.rlock()
+	defer d.runlock()
+
+	return d.betweenDAG(d.existingKeys(starts), d.existingKeys(ends))
+}
+
+// CloseDAG returns a new transitive closure DAG with the most edges that represents the same reachability relation.
+func (d *Graph[V, W]) CloseDAG(start, end V) *Graph[V, W] {
+	d.rlock()
+	defer d.runlock()
+
+	return d.closeDAG(start, end)
+}
+
+func (d *Graph[V, W]) closeDAG(start, end V) *Graph[V, W] {
+	if isNilVertice(start) || isNilVertice(end) || verticeUID(start) == verticeUID(end) {
+		return NewGraph[V, W]()
+	}
+	return d.betweenDAG(d.existingKeys(List[V]{start}), d.existingKeys(List[V]{end}))
+}
+
+func (d *Graph[V, W]) betweenDAG(starts, ends []string) *Graph[V, W] {
+	from := d.reachSet(starts, false)
+	set := make(map[string]bool)
+	for k := range d.reachSet(ends, true) {
+		if from[k] {
+			set[k] = true
 		}
-		return ok
 	}
+	return d.induced(set)
+}
 
-	iterator(startBlock)
-	return nd
+// existingKeys returns the keys of the vertices that exist in the DAG.
+func (d *Graph[V, W]) existingKeys(vs List[V]) []string {
+	keys := make([]string, 0, len(vs))
+	for _, v := range vs {
+		if isNilVertice(v) {
+			continue
+		}
+		if k := verticeUID(v); d.blocks[k] != nil {
+			keys = append(keys, k)
+		}
+	}
+	return keys
 }
 
-// ReduceDAG returns a new transitive reduction DAG with the fewest edges that represents the same reachability relation.
-func (d *DAG) ReduceDAG(start, end Vertice) *DAG {
-	nd := d.CloseDAG(start, end)
-	if nd.Len() == 0 {
-		return nd
-	}
-
-	var iterator func(n *block)
-	iterator = func(n *block) {
-		target := verticeUID(n.vertice)
-		for k := range n.prev {
-			b := nd.blocks[k]
-			// try remove relation and check other relations
-			delete(b.next, target)
-			if nd.isReachable(b, target) {
-				// clear relation
-				delete(n.prev, k)
-			} else {
-				// fix relation
-				b.next[target] = n.prev[k]
+// reachSet returns the keys of the vertices reachable from the keys (inclusive),
+// or the vertices can reach the keys if reverse is true.
+func (d *Graph[V, W]) reachSet(keys []string, reverse bool) map[string]bool {
+	set := make(map[string]bool, len(keys))
+	queue := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if !set[k] {
+			set[k] = true
+			queue = append(queue, k)
+		}
+	}
+	for len(queue) > 0 {
+		b := d.blocks[queue[0]]
+		queue = queue[1:]
+		edges := b.next
+		if reverse {
+			edges = b.prev
+		}
+		for k := range edges {
+			if !set[k] {
+				set[k] = true
+				queue = append(queue, k)
 			}
-			iterator(b)
 		}
 	}
+	return set
+}
 
-	iterator(nd.blocks[verticeUID(end)])
+// induced returns a new sub DAG with the vertices in the set and all the connecting between them.
+func (d *Graph[V, W]) induced(set map[string]bool) *Graph[V, W] {
+	nd := NewGraph[V, W]()
+	for k := range set {
+		b := d.blocks[k]
+		nb := &block[V, W]{
+			vertice: b.vertice,
+			prev:    make(map[string]W),
+			next:    make(map[string]W),
+		}
+		for kk, w := range b.prev {
+			if set[kk] {
+				nb.prev[kk] = w
+			}
+		}
+		for kk, w := range b.next {
+			if set[kk] {
+				nb.next[kk] = w
+			}
+		}
+		nd.blocks[k] = nb
+	}
 	return nd
 }
 
+// ReduceDAG returns a new transitive reduction DAG with the fewest edges that represents the same reachability relation.
+func (d *Graph[V, W]) ReduceDAG(start, end V) *Graph[V, W] {
+	d.rlock()
+	defer d.runlock()
+
+	return d.closeDAG(start, end).TransitiveReduction()
+}
+
 // Reverse returns a new DAG that all edges relation reversed.
-func (d *DAG) Reverse() *DAG {
-	nd := New()
+func (d *Graph[V, W]) Reverse() *Graph[V, W] {
+	d.rlock()
+	defer d.runlock()
+
+	nd := NewGraph[V, W]()
 	for k, b := range d.blocks {
-		nb := &block{
+		nb := &block[V, W]{
 			vertice: b.vertice,
-			prev:    make(map[string]int),
-			next:    make(map[string]int),
+			prev:    make(map[string]W),
+			next:    make(map[string]W),
 		}
 		nd.blocks[k] = nb
 	}
@@ -464,19 +826,31 @@ func (d *DAG) Reverse() *DAG {
 	return nd
 }
 
-// IterateFn ...
+// IterateFn is the iterate function of DAG.Iterate.
 type IterateFn func(cur Vertice, weight int, acc []interface{}) []interface{}
 
 // Iterate iterate the DAG' vertices with the most reachability relation paths.
-func (d *DAG) Iterate(start Vertice, init []interface{}, fn IterateFn) []interface{} {
-	res := make([]interface{}, 0)
+// The fn is called with the read lock held, it should not call any method of the DAG,
+// otherwise it may deadlock a DAG created by NewSync.
+func (d *Graph[V, W]) Iterate(start V, init []interface{}, fn func(cur V, weight W, acc []interface{}) []interface{}) []interface{} {
+	return Walk(d, start, init, fn)
+}
+
+// Walk is the typed form of Graph.Iterate, it iterate the Graph' vertices with the most reachability relation paths.
+// The fn is called with the read lock held, it should not call any method of the Graph,
+// otherwise it may deadlock a Graph created by NewSyncGraph.
+func Walk[V Vertice, W Weight, T any](d *Graph[V, W], start V, init []T, fn func(cur V, weight W, acc []T) []T) []T {
+	d.rlock()
+	defer d.runlock()
+
+	res := make([]T, 0)
 	b := d.blocks[verticeUID(start)]
 	if b == nil {
 		return res
 	}
 
-	var iterator func(b *block, weight int, acc []interface{})
-	iterator = func(b *block, weight int, acc []interface{}) {
+	var iterator func(b *block[V, W], weight W, acc []T)
+	iterator = func(b *block[V, W], weight W, acc []T) {
 		r := fn(b.vertice, weight, acc)
 		if len(b.next) == 0 {
 			res = append(res, r...)
@@ -492,112 +866,77 @@ func (d *DAG) Iterate(start Vertice, init []interface{}, fn IterateFn) []interfa
 		}
 	}
 	if init == nil {
-		init = make([]interface{}, 0)
+		init = make([]T, 0)
 	}
 	iterator(b, 0, init)
 	return res
 }
 
-type pathAcc struct {
-	weight int
-	paths  []*block
-}
-
-func (d *DAG) findPaths(start, end Vertice) []*pathAcc {
-	res := make([]*pathAcc, 0)
-	if start == nil || end == nil {
-		return res
-	}
-
-	startBlock, ok := d.blocks[verticeUID(start)]
-	if !ok {
-		return res
+// reachable returns whether the target is reachable from the block x, using the attached index if any.
+func (d *Graph[V, W]) reachable(x *block[V, W], target string) bool {
+	if d.index != nil {
+		return d.index.reachable(verticeUID(x.vertice), target)
 	}
+	return d.isReachable(x, target)
+}
 
-	endBlock, ok := d.blocks[verticeUID(end)]
-	if !ok {
-		return res
-	}
-	if startBlock == endBlock {
-		return res
-	}
-	if !d.isReachable(startBlock, verticeUID(end)) {
-		return res
+// isReachable returns whether the target is reachable from the block x by a depth-first search.
+func (d *Graph[V, W]) isReachable(x *block[V, W], target string) bool {
+	if x == nil {
+		return false
 	}
-
-	var iterator func(n *block) []*pathAcc
-	iterator = func(n *block) []*pathAcc {
-		res := make([]*pathAcc, 0)
-		for k, w := range n.next {
-			b := d.blocks[k]
-			if b == endBlock {
-				res = append(res, &pathAcc{weight: w, paths: []*block{b}})
-				continue
+	visited := make(map[string]bool)
+	stack := []*block[V, W]{x}
+	for len(stack) > 0 {
+		b := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for k := range b.next {
+			if k == target {
+				return true
 			}
-
-			for _, acc := range iterator(b) {
-				acc.weight += w
-				acc.paths = append(acc.paths, b)
-				res = append(res, acc)
+			if !visited[k] {
+				visited[k] = true
+				stack = append(stack, d.blocks[k])
 			}
 		}
-		return res
 	}
-
-	return iterator(startBlock)
-}
-
-// Shortest find a shortest paths.
-func (d *DAG) Shortest(start, end Vertice, withWeight bool) Vertices {
-	accs := d.findPaths(start, end)
-	if len(accs) == 0 {
-		return make([]Vertice, 0)
-	}
-
-	if withWeight {
-		sort.SliceStable(accs, func(i, j int) bool { return accs[i].weight < accs[j].weight })
-	} else {
-		sort.SliceStable(accs, func(i, j int) bool { return len(accs[i].paths) < len(accs[j].paths) })
-	}
-	res := make([]Vertice, 0, len(accs[0].paths)+1)
-	res = append(res, d.blocks[verticeUID(start)].vertice)
-	for i := len(accs[0].paths) - 1; i >= 0; i-- {
-		res = append(res, accs[0].paths[i].vertice)
-	}
-	return res
+	return false
 }
 
-// Longest find a longest paths.
-func (d *DAG) Longest(start, end Vertice, withWeight bool) Vertices {
-	accs := d.findPaths(start, end)
-	if len(accs) == 0 {
-		return make([]Vertice, 0)
-	}
-
-	if withWeight {
-		sort.SliceStable(accs, func(i, j int) bool { return accs[i].weight > accs[j].weight })
-	} else {
-		sort.SliceStable(accs, func(i, j int) bool { return len(accs[i].paths) > len(accs[j].paths) })
-	}
-	res := make([]Vertice, 0, len(accs[0].paths)+1)
-	res = append(res, d.blocks[verticeUID(start)].vertice)
-	for i := len(accs[0].paths) - 1; i >= 0; i-- {
-		res = append(res, accs[0].paths[i].vertice)
+// reachPath returns the keys of a shortest path from the block x to the target, returns nil if not reachable.
+func (d *Graph[V, W]) reachPath(x *block[V, W], target string) []string {
+	source := verticeUID(x.vertice)
+	parents := map[string]string{source: ""}
+	queue := []string{source}
+	for len(queue) > 0 {
+		k := queue[0]
+		queue = queue[1:]
+		for _, kk := range sortedKeys(d.blocks[k].next) {
+			if _, ok := parents[kk]; ok {
+				continue
+			}
+			parents[kk] = k
+			if kk != target {
+				queue = append(queue, kk)
+				continue
+			}
+			p := []string{kk}
+			for k := k; k != ""; k = parents[k] {
+				p = append(p, k)
+			}
+			for i, j := 0, len(p)-1; i < j; i, j = i+1, j-1 {
+				p[i], p[j] = p[j], p[i]
+			}
+			return p
+		}
 	}
-	return res
+	return nil
 }
 
-func (d *DAG) isReachable(x *block, target string) bool {
-	if x == nil {
-		return false
+func (d *Graph[V, W]) cycleError(keys []string) error {
+	path := make([]Vertice, len(keys))
+	for i, k := range keys {
+		path[i] = d.blocks[k].vertice
 	}
-	for k := range x.next {
-		if k == target {
-			return true
-		}
-		if d.isReachable(d.blocks[k], target) {
-			return true
-		}
-	}
-	return false
+	return &ErrCycle{Path: path}
 }
@@ -1,7 +1,11 @@
 package daggo_test
 
 import (
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	daggo "github.com/open-trust/dag-go"
 	"github.com/stretchr/testify/assert"
@@ -16,6 +20,18 @@ func (v V) Type() string {
 	return "test"
 }
 
+type T struct {
+	Kind string
+	Name string
+}
+
+func (t T) ID() string {
+	return t.Name
+}
+func (t T) Type() string {
+	return t.Kind
+}
+
 func TestDAG(t *testing.T) {
 	t.Run("should work", func(t *testing.T) {
 		assert := assert.New(t)
@@ -138,6 +154,47 @@ func TestDAG(t *testing.T) {
 		assert.True(x.Equal(d.CloseDAG(V("a"), V("d"))))
 	})
 
+	t.Run("DAG.ReachDAGFrom & AncestorDAG & BetweenDAG", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		assert.Nil(d.AddEdge(V("u1"), V("g1"), 1))
+		assert.Nil(d.AddEdge(V("u2"), V("g1"), 2))
+		assert.Nil(d.AddEdge(V("u2"), V("g2"), 3))
+		assert.Nil(d.AddEdge(V("u3"), V("g2"), 4))
+		assert.Nil(d.AddEdge(V("g1"), V("r1"), 5))
+		assert.Nil(d.AddEdge(V("g2"), V("r2"), 6))
+		assert.Nil(d.AddEdge(V("u1"), V("u2"), 7))
+		assert.Nil(d.AddVertice(V("o")))
+
+		x := d.ReachDAGFrom(daggo.Vertices{V("u2"), V("g1"), V("o"), V("none"), nil})
+		assert.Equal([]string{"g1", "g2", "o", "r1", "r2", "u2"}, x.Vertices("").Sort().IDs())
+		y := daggo.New()
+		assert.Nil(y.AddEdge(V("u2"), V("g1"), 2))
+		assert.Nil(y.AddEdge(V("u2"), V("g2"), 3))
+		assert.Nil(y.AddEdge(V("g1"), V("r1"), 5))
+		assert.Nil(y.AddEdge(V("g2"), V("r2"), 6))
+		assert.Nil(y.AddVertice(V("o")))
+		assert.True(y.Equal(x))
+
+		x = d.AncestorDAG(daggo.Vertices{V("r1"), V("g2")})
+		assert.Equal([]string{"g1", "g2", "r1", "u1", "u2", "u3"}, x.Vertices("").Sort().IDs())
+		assert.Equal([]string{"g1", "u2"}, x.ToVertices(V("u1")).Sort().IDs())
+		assert.Equal(0, x.ToVertices(V("g2")).Len())
+
+		x = d.BetweenDAG(daggo.Vertices{V("u2"), V("u3")}, daggo.Vertices{V("r1"), V("u3")})
+		y = daggo.New()
+		assert.Nil(y.AddEdge(V("u2"), V("g1"), 2))
+		assert.Nil(y.AddEdge(V("g1"), V("r1"), 5))
+		assert.Nil(y.AddVertice(V("u3")))
+		assert.True(y.Equal(x))
+
+		assert.True(d.CloseDAG(V("u1"), V("r2")).Equal(d.BetweenDAG(daggo.Vertices{V("u1")}, daggo.Vertices{V("r2")})))
+		assert.Equal(0, d.BetweenDAG(daggo.Vertices{V("r1")}, daggo.Vertices{V("u1")}).Len())
+		assert.Equal(0, d.ReachDAGFrom(nil).Len())
+		assert.Equal(0, d.AncestorDAG(daggo.Vertices{}).Len())
+	})
+
 	t.Run("DAG.ReduceDAG", func(t *testing.T) {
 		assert := assert.New(t)
 
@@ -172,6 +229,129 @@ func TestDAG(t *testing.T) {
 		assert.True(x.Equal(d.ReduceDAG(V("a"), V("d"))))
 	})
 
+	t.Run("DAG.AddEdge should work with deep graphs", func(t *testing.T) {
+		assert := assert.New(t)
+
+		const n = 20000
+		v := func(i int) V { return V(fmt.Sprintf("v%05d", i)) }
+		d := daggo.New()
+		// edges against the maintained order
+		for i := n - 1; i >= 0; i-- {
+			assert.Nil(d.AddEdge(v(i), v(i+1), 1))
+		}
+		assert.Nil(d.AddEdge(v(0), v(n), 1))
+		err := d.AddEdge(v(n), v(0), 1)
+		var ce *daggo.ErrCycle
+		assert.True(errors.As(err, &ce))
+		assert.Equal(3, len(ce.Path))
+
+		err = d.AddEdge(v(n-1), v(1), 1)
+		assert.True(errors.As(err, &ce))
+		assert.Equal(n, len(ce.Path))
+		assert.Equal(v(n-1), ce.Path[0])
+		assert.Equal(v(n-1), ce.Path[n-1])
+
+		// diamonds
+		d = daggo.New()
+		for i := 0; i < 100; i++ {
+			assert.Nil(d.AddEdge(v(2*i+1), v(2*i+2), 1))
+			assert.Nil(d.AddEdge(v(2*i), v(2*i+2), 1))
+			assert.Nil(d.AddEdge(v(2*i), v(2*i+1), 1))
+			if i > 0 {
+				assert.Nil(d.AddEdge(v(2*i-1), v(2*i+1), 1))
+			}
+		}
+		assert.True(errors.Is(d.AddEdge(v(200), v(0), 1), &daggo.ErrCycle{}))
+		assert.Equal(v(0), d.TopologicalSort()[0])
+	})
+
+	t.Run("DAG.AddVertice", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		assert.NotNil(d.AddVertice(nil))
+		assert.NotNil(d.AddVertice(V("")))
+		assert.Nil(d.AddVertice(V("a")))
+		assert.NotNil(d.AddVertice(V("a")))
+		assert.Equal(1, d.Len())
+		assert.Equal("a", d.GetVertice("test", "a").ID())
+		assert.Equal(daggo.Vertices{V("a")}, d.StartingVertices())
+		assert.Equal(daggo.Vertices{V("a")}, d.EndingVertices())
+
+		assert.Nil(d.AddEdge(V("a"), V("b"), 0))
+		assert.NotNil(d.AddVertice(V("b")))
+		assert.Nil(d.AddVertice(V("c")))
+		assert.Equal(daggo.Vertices{V("a"), V("c")}, d.StartingVertices().Sort())
+		assert.Equal(daggo.Vertices{V("b"), V("c")}, d.EndingVertices().Sort())
+
+		d.RemoveEdge(V("a"), V("b"))
+		assert.Equal(daggo.Vertices{V("a"), V("b"), V("c")}, d.StartingVertices().Sort())
+		assert.Equal(daggo.Vertices{V("a"), V("b"), V("c")}, d.EndingVertices().Sort())
+	})
+
+	t.Run("DAG.RemoveVertice", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		assert.Nil(d.AddEdge(V("a"), V("b"), 1))
+		assert.Nil(d.AddEdge(V("a"), V("c"), 1))
+		assert.Nil(d.AddEdge(V("b"), V("d"), 1))
+		assert.Nil(d.AddEdge(V("c"), V("d"), 1))
+
+		d.RemoveVertice(nil)
+		d.RemoveVertice(V("x"))
+		assert.Equal(4, d.Len())
+
+		d.RemoveVertice(V("b"))
+		assert.Equal(3, d.Len())
+		assert.Nil(d.GetVertice("test", "b"))
+		assert.Equal(daggo.Vertices{V("c")}, d.ToVertices(V("a")))
+		assert.Equal(daggo.Vertices{V("c")}, d.FromVertices(V("d")))
+
+		x := daggo.New()
+		assert.Nil(x.AddEdge(V("a"), V("c"), 1))
+		assert.Nil(x.AddEdge(V("c"), V("d"), 1))
+		assert.True(x.Equal(d))
+
+		d.RemoveVertice(V("c"))
+		assert.Equal(2, d.Len())
+		assert.Equal(daggo.Vertices{}, d.ToVertices(V("a")))
+		assert.Equal(daggo.Vertices{}, d.FromVertices(V("d")))
+	})
+
+	t.Run("DAG.ContractVertice", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		assert.Nil(d.AddEdge(V("a"), V("b"), 1))
+		assert.Nil(d.AddEdge(V("x"), V("b"), 2))
+		assert.Nil(d.AddEdge(V("b"), V("c"), 10))
+		assert.Nil(d.AddEdge(V("b"), V("d"), 20))
+		assert.Nil(d.AddEdge(V("a"), V("d"), 5))
+
+		a := d.Clone()
+		a.ContractVertice(V("b"), nil)
+		x := daggo.New()
+		assert.Nil(x.AddEdge(V("a"), V("c"), 11))
+		assert.Nil(x.AddEdge(V("a"), V("d"), 5))
+		assert.Nil(x.AddEdge(V("x"), V("c"), 12))
+		assert.Nil(x.AddEdge(V("x"), V("d"), 22))
+		assert.True(x.Equal(a))
+
+		d.ContractVertice(V("b"), func(prev, next int) int {
+			if prev > next {
+				return prev
+			}
+			return next
+		})
+		x = daggo.New()
+		assert.Nil(x.AddEdge(V("a"), V("c"), 10))
+		assert.Nil(x.AddEdge(V("a"), V("d"), 5))
+		assert.Nil(x.AddEdge(V("x"), V("c"), 10))
+		assert.Nil(x.AddEdge(V("x"), V("d"), 20))
+		assert.True(x.Equal(d))
+	})
+
 	t.Run("DAG.Reverse", func(t *testing.T) {
 		assert := assert.New(t)
 
@@ -202,6 +382,37 @@ func TestDAG(t *testing.T) {
 		assert.True(x.Equal(d.Reverse()))
 	})
 
+	t.Run("DAG.Descendants & Ancestors & IsReachable", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.NewGraph[T, int]()
+		assert.Nil(d.AddEdge(T{"user", "u"}, T{"group", "g2"}, 1))
+		assert.Nil(d.AddEdge(T{"user", "u"}, T{"group", "g1"}, 1))
+		assert.Nil(d.AddEdge(T{"group", "g1"}, T{"role", "r"}, 1))
+		assert.Nil(d.AddEdge(T{"group", "g2"}, T{"role", "r"}, 1))
+		assert.Nil(d.AddEdge(T{"role", "r"}, T{"resource", "x"}, 1))
+		assert.Nil(d.AddEdge(T{"group", "g2"}, T{"resource", "y"}, 1))
+		assert.Nil(d.AddVertice(T{"user", "v"}))
+
+		assert.Equal([]string{"g1", "g2", "y", "r", "x"}, d.Descendants(T{"user", "u"}, 0).IDs())
+		assert.Equal([]string{"g1", "g2", "y", "r"}, d.Descendants(T{"user", "u"}, 2).IDs())
+		assert.Equal([]string{"y", "x"}, d.Descendants(T{"user", "u"}, -1, "resource").IDs())
+		assert.Equal([]string{"g1", "g2", "y", "x"}, d.Descendants(T{"user", "u"}, 0, "group", "resource").IDs())
+		assert.Equal([]string{}, d.Descendants(T{"user", "v"}, 0).IDs())
+		assert.Equal([]string{}, d.Descendants(T{"user", "z"}, 0).IDs())
+
+		assert.Equal([]string{"r", "g1", "g2", "u"}, d.Ancestors(T{"resource", "x"}, 0).IDs())
+		assert.Equal([]string{"r"}, d.Ancestors(T{"resource", "x"}, 1).IDs())
+		assert.Equal([]string{"u"}, d.Ancestors(T{"resource", "x"}, 0, "user").IDs())
+
+		assert.True(d.IsReachable(T{"user", "u"}, T{"resource", "x"}))
+		assert.True(d.IsReachable(T{"group", "g2"}, T{"resource", "y"}))
+		assert.False(d.IsReachable(T{"group", "g1"}, T{"resource", "y"}))
+		assert.False(d.IsReachable(T{"resource", "x"}, T{"user", "u"}))
+		assert.False(d.IsReachable(T{"user", "u"}, T{"user", "u"}))
+		assert.False(d.IsReachable(T{"user", "z"}, T{"user", "u"}))
+	})
+
 	t.Run("DAG.Shortest & Longest", func(t *testing.T) {
 		assert := assert.New(t)
 
@@ -326,16 +537,173 @@ func TestDAG(t *testing.T) {
 		assert.Nil(d.AddEdge(V("d"), V("y"), 1))
 
 		j := d.JSON()
-		a := daggo.FromJSON(j)
+		a, err := daggo.FromJSON(j)
+		assert.Nil(err)
 		assert.True(d.Equal(a))
 
 		j.Edges["none"] = make(map[string]int)
-		assert.Nil(daggo.FromJSON(j))
+		a, err = daggo.FromJSON(j)
+		assert.Nil(a)
+		assert.True(errors.Is(err, &daggo.ErrUnknownVertice{}))
 
 		j = d.JSON()
 		v, ok := j.Edges["test:y"]
 		assert.True(ok)
 		v["test:a"] = 0
-		assert.Nil(daggo.FromJSON(j))
+		a, err = daggo.FromJSON(j)
+		assert.Nil(a)
+		assert.True(errors.Is(err, &daggo.ErrCycle{}))
+		var ce *daggo.ErrCycle
+		assert.True(errors.As(err, &ce))
+		assert.Equal(ce.Path[0], ce.Path[len(ce.Path)-1])
+
+		j = d.JSON()
+		j.Edges["test:y"]["test:y"] = 0
+		_, err = daggo.FromJSON(j)
+		assert.True(errors.Is(err, &daggo.ErrSelfLoop{}))
+
+		j = d.JSON()
+		j.Vertices = append(j.Vertices, V("a"))
+		_, err = daggo.FromJSON(j)
+		assert.True(errors.Is(err, &daggo.ErrDuplicateVertice{}))
+
+		j = d.JSON()
+		j.Vertices = append(j.Vertices, nil)
+		_, err = daggo.FromJSON(j)
+		assert.True(errors.Is(err, &daggo.ErrInvalidVertice{}))
+	})
+}
+
+func TestSyncDAG(t *testing.T) {
+	t.Run("should work", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.NewSync()
+		assert.Nil(d.AddEdge(V("a"), V("b"), 1))
+		assert.Nil(d.AddEdge(V("b"), V("c"), 1))
+		assert.NotNil(d.AddEdge(V("c"), V("a"), 1))
+		assert.Equal(3, d.Len())
+		assert.Equal(daggo.Vertices{V("a"), V("b"), V("c")}, d.Shortest(V("a"), V("c"), false))
+
+		a := d.Clone()
+		assert.True(d.Equal(a))
+		assert.True(d.Equal(d))
+		assert.Nil(d.Merge(d))
+		assert.Nil(a.AddEdge(V("c"), V("d"), 1))
+		assert.Nil(d.Merge(a))
+		assert.True(d.Equal(a))
+	})
+
+	t.Run("concurrent mutations and queries", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.NewSync()
+		x := daggo.NewSync()
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 200; j++ {
+					a := V(fmt.Sprintf("v%02d", (i+j)%50))
+					b := V(fmt.Sprintf("v%02d", (i+j)%50+1))
+					_ = d.AddEdge(a, b, j)
+					_ = x.AddEdge(a, b, i)
+					if j%5 == 0 {
+						d.RemoveEdge(a, b)
+					}
+				}
+			}(i)
+		}
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 50; j++ {
+					v := V(fmt.Sprintf("v%02d", j))
+					d.Len()
+					d.GetVertice("test", "v00")
+					d.Vertices("")
+					d.StartingVertices()
+					d.EndingVertices()
+					d.ToVertices(v)
+					d.FromVertices(v)
+					d.ReachDAG(v)
+					d.CloseDAG(v, V("v50"))
+					d.Shortest(v, V(fmt.Sprintf("v%02d", j+2)), true)
+					d.JSON()
+					if j%10 == 0 {
+						_ = d.Merge(x)
+						_ = x.Merge(d)
+						d.Equal(x)
+						x.Equal(d)
+					}
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		assert.Nil(d.Merge(x))
+		assert.Equal(51, d.Len())
+		assert.NotNil(d.AddEdge(V("v50"), V("v00"), 0))
+		a, err := daggo.FromJSON(d.JSON())
+		assert.Nil(err)
+		assert.True(d.Equal(a))
+	})
+}
+
+func TestGraph(t *testing.T) {
+	t.Run("should work with typed vertices and weights", func(t *testing.T) {
+		assert := assert.New(t)
+
+		user := T{"user", "x"}
+		group := T{"group", "g"}
+		role := T{"role", "r"}
+		res := T{"resource", "y"}
+
+		d := daggo.NewGraph[T, time.Duration]()
+		assert.Nil(d.AddEdge(user, group, time.Second))
+		assert.Nil(d.AddEdge(group, role, time.Second))
+		assert.Nil(d.AddEdge(role, res, time.Second))
+		assert.Nil(d.AddEdge(user, res, 5*time.Second))
+		assert.NotNil(d.AddEdge(res, user, 0))
+
+		assert.Equal(T{}, d.GetVertice("user", "none"))
+		assert.Equal(user, d.GetVertice("user", "x"))
+		assert.Equal(daggo.List[T]{user, res}, d.Shortest(user, res, false))
+		assert.Equal(daggo.List[T]{user, group, role, res}, d.Shortest(user, res, true))
+		assert.Equal(daggo.List[T]{user, res}, d.Longest(user, res, true))
+		assert.Equal([]string{"r"}, d.Vertices("role").IDs())
+		assert.Equal("group", d.ToVertices(user).Filter(func(v T) bool {
+			return v.Kind == "group"
+		})[0].Kind)
+
+		x := daggo.NewGraph[T, time.Duration]()
+		assert.Nil(x.AddEdge(user, group, time.Second))
+		assert.Nil(x.AddEdge(group, role, time.Second))
+		assert.Nil(x.AddEdge(role, res, time.Second))
+		assert.True(x.Equal(d.ReduceDAG(user, res)))
+		a, err := daggo.GraphFromJSON(d.JSON())
+		assert.Nil(err)
+		assert.True(d.Equal(a))
+
+		var total time.Duration
+		kinds := daggo.Walk(d.CloseDAG(user, res), user, nil, func(v T, w time.Duration, acc []string) []string {
+			total += w
+			return append(acc, v.Kind)
+		})
+		assert.Equal(8*time.Second, total)
+		assert.Equal([]string{"user", "group", "role", "resource", "user", "resource"}, kinds)
+	})
+
+	t.Run("should work with float weights", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.NewSyncGraph[V, float64]()
+		assert.Nil(d.AddEdge(V("a"), V("b"), 0.5))
+		assert.Nil(d.AddEdge(V("b"), V("c"), 0.25))
+		assert.Nil(d.AddEdge(V("a"), V("c"), 0.8))
+		assert.Equal(daggo.List[V]{V("a"), V("b"), V("c")}, d.Shortest(V("a"), V("c"), true))
+		assert.Equal(daggo.List[V]{V("a"), V("c")}, d.Longest(V("a"), V("c"), true))
 	})
 }
@@ -0,0 +1,152 @@
+package daggo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// DiagramOptions is the options of Graph.WriteMermaid and Graph.WritePlantUML.
+type DiagramOptions struct {
+	// Direction is the layout direction, one of "TB" (default), "BT", "LR" and "RL".
+	// PlantUML only supports top to bottom ("TB", "BT") and left to right ("LR", "RL").
+	Direction string
+	// GroupByType groups vertices of the same type into a subgraph (Mermaid) or a package (PlantUML).
+	GroupByType bool
+	// ShowWeights labels edges with weights.
+	ShowWeights bool
+}
+
+// diagram is the vertices and edges of a DAG in a stable order for writing diagrams.
+type diagram struct {
+	keys   []string            // vertices UID, sorted
+	names  map[string]string   // vertice UID to node name
+	types  []string            // vertices types in order of appearance
+	groups map[string][]string // vertice type to vertices UID
+	edges  [][3]string         // from, to and weight
+}
+
+func (d *Graph[V, W]) diagram() *diagram {
+	g := &diagram{
+		keys:   make([]string, 0, len(d.blocks)),
+		names:  make(map[string]string, len(d.blocks)),
+		groups: make(map[string][]string),
+	}
+	for k := range d.blocks {
+		g.keys = append(g.keys, k)
+	}
+	sort.Strings(g.keys)
+	for i, k := range g.keys {
+		g.names[k] = fmt.Sprintf("n%d", i)
+		ty := d.blocks[k].vertice.Type()
+		if _, ok := g.groups[ty]; !ok {
+			g.types = append(g.types, ty)
+		}
+		g.groups[ty] = append(g.groups[ty], k)
+	}
+	for _, k := range g.keys {
+		b := d.blocks[k]
+		nks := make([]string, 0, len(b.next))
+		for kk := range b.next {
+			nks = append(nks, kk)
+		}
+		sort.Strings(nks)
+		for _, kk := range nks {
+			g.edges = append(g.edges, [3]string{k, kk, formatWeight(b.next[kk])})
+		}
+	}
+	return g
+}
+
+// WriteMermaid writes the DAG as a Mermaid flowchart, vertices are labelled by "type:id".
+// The opts can be nil.
+func (d *Graph[V, W]) WriteMermaid(w io.Writer, opts *DiagramOptions) error {
+	if opts == nil {
+		opts = &DiagramOptions{}
+	}
+	dir := strings.ToUpper(opts.Direction)
+	switch dir {
+	case "TB", "BT", "LR", "RL":
+	default:
+		dir = "TB"
+	}
+
+	d.rlock()
+	g := d.diagram()
+	d.runlock()
+
+	quote := func(s string) string {
+		return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "flowchart %s\n", dir)
+	if opts.GroupByType {
+		for i, ty := range g.types {
+			fmt.Fprintf(bw, "    subgraph g%d [%s]\n", i, quote(ty))
+			for _, k := range g.groups[ty] {
+				fmt.Fprintf(bw, "        %s[%s]\n", g.names[k], quote(k))
+			}
+			fmt.Fprint(bw, "    end\n")
+		}
+	} else {
+		for _, k := range g.keys {
+			fmt.Fprintf(bw, "    %s[%s]\n", g.names[k], quote(k))
+		}
+	}
+	for _, e := range g.edges {
+		if opts.ShowWeights {
+			fmt.Fprintf(bw, "    %s -->|%s| %s\n", g.names[e[0]], quote(e[2]), g.names[e[1]])
+		} else {
+			fmt.Fprintf(bw, "    %s --> %s\n", g.names[e[0]], g.names[e[1]])
+		}
+	}
+	return bw.Flush()
+}
+
+// WritePlantUML writes the DAG as a PlantUML diagram, vertices are labelled by "type:id".
+// The opts can be nil.
+func (d *Graph[V, W]) WritePlantUML(w io.Writer, opts *DiagramOptions) error {
+	if opts == nil {
+		opts = &DiagramOptions{}
+	}
+
+	d.rlock()
+	g := d.diagram()
+	d.runlock()
+
+	quote := func(s string) string {
+		return `"` + strings.ReplaceAll(s, `"`, "'") + `"`
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprint(bw, "@startuml\n")
+	switch strings.ToUpper(opts.Direction) {
+	case "LR", "RL":
+		fmt.Fprint(bw, "left to right direction\n")
+	default:
+		fmt.Fprint(bw, "top to bottom direction\n")
+	}
+	if opts.GroupByType {
+		for _, ty := range g.types {
+			fmt.Fprintf(bw, "package %s {\n", quote(ty))
+			for _, k := range g.groups[ty] {
+				fmt.Fprintf(bw, "  rectangle %s as %s\n", quote(k), g.names[k])
+			}
+			fmt.Fprint(bw, "}\n")
+		}
+	} else {
+		for _, k := range g.keys {
+			fmt.Fprintf(bw, "rectangle %s as %s\n", quote(k), g.names[k])
+		}
+	}
+	for _, e := range g.edges {
+		if opts.ShowWeights {
+			fmt.Fprintf(bw, "%s --> %s : %s\n", g.names[e[0]], g.names[e[1]], e[2])
+		} else {
+			fmt.Fprintf(bw, "%s --> %s\n", g.names[e[0]], g.names[e[1]])
+		}
+	}
+	fmt.Fprint(bw, "@enduml\n")
+	return bw.Flush()
+}
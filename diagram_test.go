@@ -0,0 +1,98 @@
+package daggo_test
+
+import (
+	"bytes"
+	"testing"
+
+	daggo "github.com/open-trust/dag-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiagram(t *testing.T) {
+	d := daggo.NewGraph[T, float64]()
+	assert.Nil(t, d.AddEdge(T{"user", "alice"}, T{"group", "dev"}, 1.5))
+	assert.Nil(t, d.AddEdge(T{"group", "dev"}, T{"role", `"admin"`}, 2))
+	assert.Nil(t, d.AddEdge(T{"user", "bob"}, T{"group", "dev"}, 1))
+
+	t.Run("DAG.WriteMermaid", func(t *testing.T) {
+		assert := assert.New(t)
+
+		var buf bytes.Buffer
+		assert.Nil(d.WriteMermaid(&buf, nil))
+		assert.Equal(`flowchart TB
+    n0["group:dev"]
+    n1["role:#quot;admin#quot;"]
+    n2["user:alice"]
+    n3["user:bob"]
+    n0 --> n1
+    n2 --> n0
+    n3 --> n0
+`, buf.String())
+
+		buf.Reset()
+		assert.Nil(d.WriteMermaid(&buf, &daggo.DiagramOptions{Direction: "lr", GroupByType: true, ShowWeights: true}))
+		assert.Equal(`flowchart LR
+    subgraph g0 ["group"]
+        n0["group:dev"]
+    end
+    subgraph g1 ["role"]
+        n1["role:#quot;admin#quot;"]
+    end
+    subgraph g2 ["user"]
+        n2["user:alice"]
+        n3["user:bob"]
+    end
+    n0 -->|"2"| n1
+    n2 -->|"1.5"| n0
+    n3 -->|"1"| n0
+`, buf.String())
+
+		buf.Reset()
+		assert.Nil(d.ReachDAG(T{"user", "bob"}).WriteMermaid(&buf, &daggo.DiagramOptions{Direction: "up"}))
+		assert.Equal(`flowchart TB
+    n0["group:dev"]
+    n1["role:#quot;admin#quot;"]
+    n2["user:bob"]
+    n0 --> n1
+    n2 --> n0
+`, buf.String())
+	})
+
+	t.Run("DAG.WritePlantUML", func(t *testing.T) {
+		assert := assert.New(t)
+
+		var buf bytes.Buffer
+		assert.Nil(d.WritePlantUML(&buf, nil))
+		assert.Equal(`@startuml
+top to bottom direction
+rectangle "group:dev" as n0
+rectangle "role:'admin'" as n1
+rectangle "user:alice" as n2
+rectangle "user:bob" as n3
+n0 --> n1
+n2 --> n0
+n3 --> n0
+@enduml
+`, buf.String())
+
+		buf.Reset()
+		assert.Nil(d.WritePlantUML(&buf, &daggo.DiagramOptions{Direction: "LR", GroupByType: true, ShowWeights: true}))
+		assert.Equal(`@startuml
+left to right direction
+package "group" {
+  rectangle "group:dev" as n0
+}
+package "role" {
+  rectangle "role:'admin'" as n1
+}
+package "user" {
+  rectangle "user:alice" as n2
+  rectangle "user:bob" as n3
+}
+n0 --> n1 : 2
+n2 --> n0 : 1.5
+n3 --> n0 : 1
+@enduml
+`, buf.String())
+	})
+}
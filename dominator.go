@@ -0,0 +1,184 @@
+package daggo
+
+import (
+	"sort"
+)
+
+// DominatorTree is the dominator tree of the vertices reachable from a root vertice.
+// A vertice a dominates b if every path from the root to b passes through a.
+// For a post dominator tree, the paths are from b to the root.
+type DominatorTree[V Vertice] struct {
+	root     string
+	blocks   map[string]V
+	idom     map[string]string
+	children map[string][]string
+	depth    map[string]int
+}
+
+// Dominators returns the dominator tree of the vertices reachable from start, returns nil if start not exists.
+func (d *Graph[V, W]) Dominators(start V) *DominatorTree[V] {
+	d.rlock()
+	defer d.runlock()
+
+	if isNilVertice(start) {
+		return nil
+	}
+	return d.dominatorTree(verticeUID(start), false)
+}
+
+// PostDominators returns the post dominator tree of the vertices that can reach end, returns nil if end not exists.
+func (d *Graph[V, W]) PostDominators(end V) *DominatorTree[V] {
+	d.rlock()
+	defer d.runlock()
+
+	if isNilVertice(end) {
+		return nil
+	}
+	return d.dominatorTree(verticeUID(end), true)
+}
+
+// MustPassThrough returns the vertices that every path from start to end passes through, in the order of the paths.
+// The start and end vertices are not included.
+func (d *Graph[V, W]) MustPassThrough(start, end V) List[V] {
+	t := d.Dominators(start)
+	if t == nil {
+		return make([]V, 0)
+	}
+	// the dominators of end start with the root
+	if res := t.Dominators(end); len(res) > 0 {
+		return res[1:]
+	}
+	return make([]V, 0)
+}
+
+func (d *Graph[V, W]) dominatorTree(root string, reverse bool) *DominatorTree[V] {
+	order := d.reachOrder(root, reverse)
+	if order == nil {
+		return nil
+	}
+	if reverse {
+		// from the root to its ancestors
+		for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+			order[i], order[j] = order[j], order[i]
+		}
+	}
+
+	t := &DominatorTree[V]{
+		root:     root,
+		blocks:   make(map[string]V, len(order)),
+		idom:     make(map[string]string, len(order)),
+		children: make(map[string][]string),
+		depth:    make(map[string]int, len(order)),
+	}
+	pos := make(map[string]int, len(order))
+	for i, k := range order {
+		pos[k] = i
+		t.blocks[k] = d.blocks[k].vertice
+	}
+	// the immediate dominator of a vertice is the nearest common dominator of its predecessors,
+	// which come before it in topological order.
+	intersect := func(a, b string) string {
+		for a != b {
+			for pos[a] > pos[b] {
+				a = t.idom[a]
+			}
+			for pos[b] > pos[a] {
+				b = t.idom[b]
+			}
+		}
+		return a
+	}
+	for _, k := range order[1:] {
+		preds := d.blocks[k].prev
+		if reverse {
+			preds = d.blocks[k].next
+		}
+		idom := ""
+		for kk := range preds {
+			if _, ok := pos[kk]; !ok {
+				continue
+			}
+			if idom == "" {
+				idom = kk
+			} else {
+				idom = intersect(idom, kk)
+			}
+		}
+		t.idom[k] = idom
+		t.depth[k] = t.depth[idom] + 1
+		t.children[idom] = append(t.children[idom], k)
+	}
+	for _, ks := range t.children {
+		sort.Strings(ks)
+	}
+	return t
+}
+
+// Root returns the root vertice of the tree.
+func (t *DominatorTree[V]) Root() V {
+	return t.blocks[t.root]
+}
+
+// Len returns the number of vertices in the tree.
+func (t *DominatorTree[V]) Len() int {
+	return len(t.blocks)
+}
+
+// Idom returns the immediate dominator of the vertice v, returns false if v is the root or not in the tree.
+func (t *DominatorTree[V]) Idom(v V) (V, bool) {
+	var zero V
+	if isNilVertice(v) {
+		return zero, false
+	}
+	k, ok := t.idom[verticeUID(v)]
+	if !ok {
+		return zero, false
+	}
+	return t.blocks[k], true
+}
+
+// Children returns the vertices that the vertice v immediately dominates, sorted by UID.
+func (t *DominatorTree[V]) Children(v V) List[V] {
+	res := make([]V, 0)
+	if isNilVertice(v) {
+		return res
+	}
+	for _, k := range t.children[verticeUID(v)] {
+		res = append(res, t.blocks[k])
+	}
+	return res
+}
+
+// Dominates returns whether the vertice a dominates b, a vertice dominates itself.
+func (t *DominatorTree[V]) Dominates(a, b V) bool {
+	if isNilVertice(a) || isNilVertice(b) {
+		return false
+	}
+	ka, kb := verticeUID(a), verticeUID(b)
+	if _, ok := t.blocks[ka]; !ok {
+		return false
+	}
+	if _, ok := t.blocks[kb]; !ok {
+		return false
+	}
+	for t.depth[kb] > t.depth[ka] {
+		kb = t.idom[kb]
+	}
+	return ka == kb
+}
+
+// Dominators returns the vertices that dominate the vertice v from the root to its immediate dominator.
+func (t *DominatorTree[V]) Dominators(v V) List[V] {
+	res := make([]V, 0)
+	if isNilVertice(v) {
+		return res
+	}
+	k, ok := t.idom[verticeUID(v)]
+	for ; ok; k, ok = t.idom[k] {
+		res = append(res, t.blocks[k])
+	}
+	for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+		res[i], res[j] = res[j], res[i]
+	}
+	return res
+}
@@ -0,0 +1,117 @@
+package daggo_test
+
+import (
+	"testing"
+
+	daggo "github.com/open-trust/dag-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDominator(t *testing.T) {
+	// a -> b -> c -> e -> f
+	//   \-> d --^    \-> g -> h
+	//                 x -----^
+	t.Run("DAG.Dominators", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		assert.Nil(d.AddEdge(V("a"), V("b"), 1))
+		assert.Nil(d.AddEdge(V("b"), V("c"), 1))
+		assert.Nil(d.AddEdge(V("a"), V("d"), 1))
+		assert.Nil(d.AddEdge(V("d"), V("c"), 1))
+		assert.Nil(d.AddEdge(V("c"), V("e"), 1))
+		assert.Nil(d.AddEdge(V("e"), V("f"), 1))
+		assert.Nil(d.AddEdge(V("e"), V("g"), 1))
+		assert.Nil(d.AddEdge(V("g"), V("h"), 1))
+		assert.Nil(d.AddEdge(V("x"), V("h"), 1))
+		assert.Nil(d.AddEdge(V("f"), V("h"), 1))
+
+		tr := d.Dominators(V("a"))
+		assert.Equal(V("a"), tr.Root())
+		assert.Equal(8, tr.Len())
+
+		v, ok := tr.Idom(V("c"))
+		assert.True(ok)
+		assert.Equal(V("a"), v)
+		v, ok = tr.Idom(V("h"))
+		assert.True(ok)
+		assert.Equal(V("e"), v)
+		_, ok = tr.Idom(V("a"))
+		assert.False(ok)
+		_, ok = tr.Idom(V("x"))
+		assert.False(ok)
+
+		assert.Equal([]string{"b", "c", "d"}, tr.Children(V("a")).IDs())
+		assert.Equal([]string{"f", "g", "h"}, tr.Children(V("e")).IDs())
+		assert.Equal([]string{}, tr.Children(V("b")).IDs())
+		assert.Equal([]string{"a", "c", "e"}, tr.Dominators(V("h")).IDs())
+		assert.Equal([]string{}, tr.Dominators(V("a")).IDs())
+
+		assert.True(tr.Dominates(V("c"), V("h")))
+		assert.True(tr.Dominates(V("a"), V("a")))
+		assert.False(tr.Dominates(V("b"), V("c")))
+		assert.False(tr.Dominates(V("g"), V("h")))
+		assert.False(tr.Dominates(V("h"), V("c")))
+		assert.False(tr.Dominates(V("x"), V("h")))
+
+		assert.Nil(d.Dominators(V("none")))
+		assert.Nil(d.Dominators(nil))
+		assert.Equal(1, d.Dominators(V("h")).Len())
+	})
+
+	t.Run("DAG.PostDominators", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		assert.Nil(d.AddEdge(V("a"), V("b"), 1))
+		assert.Nil(d.AddEdge(V("b"), V("c"), 1))
+		assert.Nil(d.AddEdge(V("a"), V("d"), 1))
+		assert.Nil(d.AddEdge(V("d"), V("c"), 1))
+		assert.Nil(d.AddEdge(V("c"), V("e"), 1))
+		assert.Nil(d.AddEdge(V("e"), V("f"), 1))
+		assert.Nil(d.AddEdge(V("e"), V("g"), 1))
+		assert.Nil(d.AddEdge(V("g"), V("h"), 1))
+		assert.Nil(d.AddEdge(V("x"), V("h"), 1))
+		assert.Nil(d.AddEdge(V("f"), V("h"), 1))
+
+		tr := d.PostDominators(V("h"))
+		assert.Equal(V("h"), tr.Root())
+		assert.Equal(9, tr.Len())
+
+		v, ok := tr.Idom(V("c"))
+		assert.True(ok)
+		assert.Equal(V("e"), v)
+		v, ok = tr.Idom(V("a"))
+		assert.True(ok)
+		assert.Equal(V("c"), v)
+		assert.Equal([]string{"h", "e", "c"}, tr.Dominators(V("a")).IDs())
+		assert.Equal([]string{"e", "f", "g", "x"}, tr.Children(V("h")).IDs())
+		assert.True(tr.Dominates(V("c"), V("b")))
+		assert.False(tr.Dominates(V("c"), V("x")))
+		assert.Nil(d.PostDominators(V("none")))
+	})
+
+	t.Run("DAG.MustPassThrough", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		assert.Nil(d.AddEdge(V("a"), V("b"), 1))
+		assert.Nil(d.AddEdge(V("b"), V("c"), 1))
+		assert.Nil(d.AddEdge(V("a"), V("d"), 1))
+		assert.Nil(d.AddEdge(V("d"), V("c"), 1))
+		assert.Nil(d.AddEdge(V("c"), V("e"), 1))
+		assert.Nil(d.AddEdge(V("e"), V("f"), 1))
+		assert.Nil(d.AddEdge(V("e"), V("g"), 1))
+		assert.Nil(d.AddEdge(V("g"), V("h"), 1))
+		assert.Nil(d.AddEdge(V("x"), V("h"), 1))
+		assert.Nil(d.AddEdge(V("f"), V("h"), 1))
+
+		assert.Equal([]string{"c", "e"}, d.MustPassThrough(V("a"), V("h")).IDs())
+		assert.Equal([]string{"c", "e"}, d.MustPassThrough(V("b"), V("f")).IDs())
+		assert.Equal([]string{}, d.MustPassThrough(V("a"), V("c")).IDs())
+		assert.Equal([]string{}, d.MustPassThrough(V("a"), V("b")).IDs())
+		assert.Equal([]string{}, d.MustPassThrough(V("a"), V("a")).IDs())
+		assert.Equal([]string{}, d.MustPassThrough(V("a"), V("x")).IDs())
+		assert.Equal([]string{}, d.MustPassThrough(V("none"), V("h")).IDs())
+	})
+}
@@ -0,0 +1,596 @@
+package daggo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// DOTOptions is the options of Graph.WriteDOT.
+type DOTOptions[V Vertice] struct {
+	// Name is the name of the digraph, defaults to "dag".
+	Name string
+	// ClusterByType groups vertices of the same type into a cluster.
+	ClusterByType bool
+	// Colors maps vertice types to fill colors.
+	Colors map[string]string
+	// Highlight is a path of vertices to highlight, e.g. the result of Shortest.
+	Highlight List[V]
+	// HighlightColor is the color of the highlighted path, defaults to "red".
+	HighlightColor string
+}
+
+// WriteDOT writes the DAG in Graphviz DOT language, vertices are labelled by "type:id" and edges by weights.
+// The opts can be nil.
+func (d *Graph[V, W]) WriteDOT(w io.Writer, opts *DOTOptions[V]) error {
+	if opts == nil {
+		opts = &DOTOptions[V]{}
+	}
+	name := opts.Name
+	if name == "" {
+		name = "dag"
+	}
+	color := opts.HighlightColor
+	if color == "" {
+		color = "red"
+	}
+	highlight := make(map[string]bool)
+	highlightEdges := make(map[[2]string]bool)
+	for i, v := range opts.Highlight {
+		highlight[verticeUID(v)] = true
+		if i > 0 {
+			highlightEdges[[2]string{verticeUID(opts.Highlight[i-1]), verticeUID(v)}] = true
+		}
+	}
+
+	d.rlock()
+	defer d.runlock()
+
+	keys := make([]string, 0, len(d.blocks))
+	for k := range d.blocks {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	node := func(bw *bufio.Writer, indent, k string) {
+		v := d.blocks[k].vertice
+		attrs := []string{"label=" + dotQuote(k)}
+		if c, ok := opts.Colors[v.Type()]; ok {
+			attrs = append(attrs, "style=filled", "fillcolor="+dotQuote(c))
+		}
+		if highlight[k] {
+			attrs = append(attrs, "color="+dotQuote(color), "penwidth=2")
+		}
+		fmt.Fprintf(bw, "%s%s [%s];\n", indent, dotQuote(k), strings.Join(attrs, ", "))
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "digraph %s {\n", dotQuote(name))
+	if opts.ClusterByType {
+		types := make([]string, 0)
+		clusters := make(map[string][]string)
+		for _, k := range keys {
+			ty := d.blocks[k].vertice.Type()
+			if _, ok := clusters[ty]; !ok {
+				types = append(types, ty)
+			}
+			clusters[ty] = append(clusters[ty], k)
+		}
+		for _, ty := range types {
+			fmt.Fprintf(bw, "\tsubgraph %s {\n", dotQuote("cluster_"+ty))
+			fmt.Fprintf(bw, "\t\tlabel=%s;\n", dotQuote(ty))
+			for _, k := range clusters[ty] {
+				node(bw, "\t\t", k)
+			}
+			fmt.Fprint(bw, "\t}\n")
+		}
+	} else {
+		for _, k := range keys {
+			node(bw, "\t", k)
+		}
+	}
+	for _, k := range keys {
+		b := d.blocks[k]
+		nks := make([]string, 0, len(b.next))
+		for kk := range b.next {
+			nks = append(nks, kk)
+		}
+		sort.Strings(nks)
+		for _, kk := range nks {
+			attrs := []string{"label=" + dotQuote(formatWeight(b.next[kk]))}
+			if highlightEdges[[2]string{k, kk}] {
+				attrs = append(attrs, "color="+dotQuote(color), "penwidth=2")
+			}
+			fmt.Fprintf(bw, "\t%s -> %s [%s];\n", dotQuote(k), dotQuote(kk), strings.Join(attrs, ", "))
+		}
+	}
+	fmt.Fprint(bw, "}\n")
+	return bw.Flush()
+}
+
+// ParseDOT returns a DAG from a digraph in Graphviz DOT language.
+// A node named "type:id" is created by fn(type, id), a node name without ":" is created by fn("", name).
+// The edge weight is read from the "weight" attribute, or the "label" attribute if it is a number.
+func ParseDOT(r io.Reader, fn func(ty, id string) (Vertice, error)) (*DAG, error) {
+	return ParseGraphDOT[Vertice, int](r, fn)
+}
+
+// ParseGraphDOT is the typed form of ParseDOT.
+func ParseGraphDOT[V Vertice, W Weight](r io.Reader, fn func(ty, id string) (V, error)) (*Graph[V, W], error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	p := &dotParser{lexer: &dotLexer{src: []rune(string(data))}, nodes: make(map[string]bool)}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+
+	nd := NewGraph[V, W]()
+	uids := make(map[string]string, len(p.names))
+	for _, name := range p.names {
+		ty, id := "", name
+		if i := strings.Index(name, ":"); i >= 0 {
+			ty, id = name[:i], name[i+1:]
+		}
+		v, err := fn(ty, id)
+		if err != nil {
+			return nil, err
+		}
+		if isNilVertice(v) || v.ID() == "" {
+			return nil, &ErrInvalidVertice{Vertice: v}
+		}
+		k := verticeUID(v)
+		uids[name] = k
+		if _, ok := nd.blocks[k]; !ok {
+			nd.blocks[k] = &block[V, W]{
+				vertice: v,
+				prev:    make(map[string]W),
+				next:    make(map[string]W),
+			}
+		}
+	}
+	for _, e := range p.edges {
+		var w W
+		if s, ok := e.attrs["weight"]; ok {
+			if w, err = parseWeight[W](s); err != nil {
+				return nil, fmt.Errorf("invalid weight of edge %q -> %q: %w", e.from, e.to, err)
+			}
+		} else if s, ok := e.attrs["label"]; ok {
+			if x, err := parseWeight[W](s); err == nil {
+				w = x
+			}
+		}
+		startID, endID := uids[e.from], uids[e.to]
+		if startID == endID {
+			return nil, &ErrSelfLoop{Vertice: nd.blocks[startID].vertice}
+		}
+		nd.blocks[startID].next[endID] = w
+		nd.blocks[endID].prev[startID] = w
+	}
+	if cycle := nd.cycleKeys(); cycle != nil {
+		return nil, nd.cycleError(cycle)
+	}
+	return nd, nil
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func formatWeight[W Weight](w W) string {
+	v := reflect.ValueOf(w)
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10)
+	default:
+		return strconv.FormatInt(v.Int(), 10)
+	}
+}
+
+func parseWeight[W Weight](s string) (W, error) {
+	var w W
+	v := reflect.ValueOf(&w).Elem()
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		x, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return w, err
+		}
+		v.SetFloat(x)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		x, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return w, err
+		}
+		v.SetUint(x)
+	default:
+		x, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return w, err
+		}
+		v.SetInt(x)
+	}
+	return w, nil
+}
+
+type dotToken struct {
+	text string
+	id   bool // an ID, quoted or not
+}
+
+func (t *dotToken) String() string {
+	if t == nil {
+		return "end of graph"
+	}
+	return strconv.Quote(t.text)
+}
+
+type dotLexer struct {
+	src []rune
+	pos int
+	tok *dotToken
+}
+
+func (l *dotLexer) peek() (*dotToken, error) {
+	if l.tok == nil {
+		tok, err := l.scan()
+		if err != nil {
+			return nil, err
+		}
+		l.tok = tok
+	}
+	return l.tok, nil
+}
+
+func (l *dotLexer) next() (*dotToken, error) {
+	tok, err := l.peek()
+	l.tok = nil
+	return tok, err
+}
+
+// scan returns the next token, returns nil at the end of source.
+func (l *dotLexer) scan() (*dotToken, error) {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case unicode.IsSpace(c):
+			l.pos++
+		case c == '#' && (l.pos == 0 || l.src[l.pos-1] == '\n'):
+			l.skipUntil("\n")
+		case c == '/' && l.at("//"):
+			l.skipUntil("\n")
+		case c == '/' && l.at("/*"):
+			l.skipUntil("*/")
+		default:
+			return l.scanToken()
+		}
+	}
+	return nil, nil
+}
+
+func (l *dotLexer) scanToken() (*dotToken, error) {
+	c := l.src[l.pos]
+	switch {
+	case strings.ContainsRune("{}[]=;,:", c):
+		l.pos++
+		return &dotToken{text: string(c)}, nil
+	case l.at("->"):
+		l.pos += 2
+		return &dotToken{text: "->"}, nil
+	case l.at("--"):
+		return nil, fmt.Errorf("undirected graph is not supported")
+	case c == '"':
+		var sb strings.Builder
+		for l.pos++; l.pos < len(l.src); l.pos++ {
+			c = l.src[l.pos]
+			switch {
+			case c == '"':
+				l.pos++
+				return &dotToken{text: sb.String(), id: true}, nil
+			case c == '\\' && l.pos+1 < len(l.src) && (l.src[l.pos+1] == '"' || l.src[l.pos+1] == '\\'):
+				l.pos++
+				sb.WriteRune(l.src[l.pos])
+			case c == '\\' && l.pos+1 < len(l.src) && l.src[l.pos+1] == '\n':
+				l.pos++
+			default:
+				sb.WriteRune(c)
+			}
+		}
+		return nil, fmt.Errorf("unterminated string")
+	case c == '<':
+		depth, start := 0, l.pos
+		for ; l.pos < len(l.src); l.pos++ {
+			switch l.src[l.pos] {
+			case '<':
+				depth++
+			case '>':
+				depth--
+				if depth == 0 {
+					l.pos++
+					return &dotToken{text: string(l.src[start+1 : l.pos-1]), id: true}, nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("unterminated HTML string")
+	case c == '_' || c == '-' || c == '.' || unicode.IsLetter(c) || unicode.IsDigit(c):
+		start := l.pos
+		for l.pos < len(l.src) {
+			c = l.src[l.pos]
+			if c != '_' && c != '.' && !unicode.IsLetter(c) && !unicode.IsDigit(c) && !(c == '-' && l.pos == start) {
+				break
+			}
+			l.pos++
+		}
+		return &dotToken{text: string(l.src[start:l.pos]), id: true}, nil
+	}
+	return nil, fmt.Errorf("unexpected character %q at %d", c, l.pos)
+}
+
+func (l *dotLexer) at(s string) bool {
+	for i, c := range []rune(s) {
+		if l.pos+i >= len(l.src) || l.src[l.pos+i] != c {
+			return false
+		}
+	}
+	return true
+}
+
+func (l *dotLexer) skipUntil(s string) {
+	for l.pos < len(l.src) && !l.at(s) {
+		l.pos++
+	}
+	l.pos += len(s)
+}
+
+type dotEdge struct {
+	from, to string
+	attrs    map[string]string
+}
+
+type dotParser struct {
+	lexer *dotLexer
+	names []string
+	nodes map[string]bool
+	edges []dotEdge
+}
+
+func (p *dotParser) node(name string) {
+	if !p.nodes[name] {
+		p.nodes[name] = true
+		p.names = append(p.names, name)
+	}
+}
+
+func (p *dotParser) expect(text string) error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	if tok == nil || tok.id || tok.text != text {
+		return fmt.Errorf("expected %q, got %s", text, tok)
+	}
+	return nil
+}
+
+// keyword returns whether the next token is the keyword, and consumes it if so.
+func (p *dotParser) keyword(kw string) (bool, error) {
+	tok, err := p.lexer.peek()
+	if err != nil || tok == nil || !tok.id || !strings.EqualFold(tok.text, kw) {
+		return false, err
+	}
+	p.lexer.next()
+	return true, nil
+}
+
+func (p *dotParser) punct(text string) (bool, error) {
+	tok, err := p.lexer.peek()
+	if err != nil || tok == nil || tok.id || tok.text != text {
+		return false, err
+	}
+	p.lexer.next()
+	return true, nil
+}
+
+func (p *dotParser) parse() error {
+	if _, err := p.keyword("strict"); err != nil {
+		return err
+	}
+	if ok, err := p.keyword("digraph"); err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("expected digraph")
+	}
+	if tok, err := p.lexer.peek(); err != nil {
+		return err
+	} else if tok != nil && tok.id {
+		p.lexer.next()
+	}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	if _, err := p.stmts(); err != nil {
+		return err
+	}
+	if tok, err := p.lexer.next(); err != nil {
+		return err
+	} else if tok != nil {
+		return fmt.Errorf("unexpected %s after graph", tok)
+	}
+	return nil
+}
+
+// stmts parses statements until "}", returns the nodes in the statements.
+func (p *dotParser) stmts() ([]string, error) {
+	nodes := make([]string, 0)
+	for {
+		if ok, err := p.punct("}"); err != nil || ok {
+			return nodes, err
+		}
+		if ok, err := p.punct(";"); err != nil {
+			return nil, err
+		} else if ok {
+			continue
+		}
+		ns, err := p.stmt()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, ns...)
+	}
+}
+
+func (p *dotParser) stmt() ([]string, error) {
+	tok, err := p.lexer.peek()
+	if err != nil {
+		return nil, err
+	}
+	if tok == nil {
+		return nil, fmt.Errorf("unexpected end of graph")
+	}
+	if tok.id && (strings.EqualFold(tok.text, "graph") || strings.EqualFold(tok.text, "node") || strings.EqualFold(tok.text, "edge")) {
+		p.lexer.next()
+		_, err := p.attrs()
+		return nil, err
+	}
+
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	if ok, err := p.punct("="); err != nil {
+		return nil, err
+	} else if ok {
+		// graph attribute "ID = ID"
+		_, err := p.id()
+		return nil, err
+	}
+
+	nodes := append([]string{}, left...)
+	ends := [][]string{left}
+	for {
+		ok, err := p.punct("->")
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		right, err := p.operand()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, right...)
+		ends = append(ends, right)
+	}
+	attrs, err := p.attrs()
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range nodes {
+		p.node(n)
+	}
+	for i := 1; i < len(ends); i++ {
+		for _, from := range ends[i-1] {
+			for _, to := range ends[i] {
+				p.edges = append(p.edges, dotEdge{from: from, to: to, attrs: attrs})
+			}
+		}
+	}
+	return nodes, nil
+}
+
+// operand parses a node ID or a subgraph, returns the nodes.
+func (p *dotParser) operand() ([]string, error) {
+	if ok, err := p.keyword("subgraph"); err != nil {
+		return nil, err
+	} else if ok {
+		if tok, err := p.lexer.peek(); err != nil {
+			return nil, err
+		} else if tok != nil && tok.id {
+			p.lexer.next()
+		}
+		if err := p.expect("{"); err != nil {
+			return nil, err
+		}
+		return p.stmts()
+	}
+	if ok, err := p.punct("{"); err != nil {
+		return nil, err
+	} else if ok {
+		return p.stmts()
+	}
+
+	name, err := p.id()
+	if err != nil {
+		return nil, err
+	}
+	// skip the port
+	for {
+		ok, err := p.punct(":")
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		if _, err := p.id(); err != nil {
+			return nil, err
+		}
+	}
+	return []string{name}, nil
+}
+
+func (p *dotParser) id() (string, error) {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return "", err
+	}
+	if tok == nil || !tok.id {
+		return "", fmt.Errorf("expected ID, got %s", tok)
+	}
+	return tok.text, nil
+}
+
+// attrs parses attribute lists, returns the attributes.
+func (p *dotParser) attrs() (map[string]string, error) {
+	attrs := make(map[string]string)
+	for {
+		ok, err := p.punct("[")
+		if err != nil || !ok {
+			return attrs, err
+		}
+		for {
+			if ok, err := p.punct("]"); err != nil {
+				return nil, err
+			} else if ok {
+				break
+			}
+			k, err := p.id()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("="); err != nil {
+				return nil, err
+			}
+			v, err := p.id()
+			if err != nil {
+				return nil, err
+			}
+			attrs[k] = v
+			if _, err := p.punct(","); err != nil {
+				return nil, err
+			}
+			if _, err := p.punct(";"); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package daggo_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	daggo "github.com/open-trust/dag-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func newT(ty, id string) (T, error) {
+	if ty == "" {
+		ty = "user"
+	}
+	return T{ty, id}, nil
+}
+
+func TestDOT(t *testing.T) {
+	t.Run("DAG.WriteDOT", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		assert.Nil(d.AddEdge(V("a"), V("b"), 1))
+		assert.Nil(d.AddEdge(V("b"), V("c"), 2))
+		assert.Nil(d.AddEdge(V("a"), V("c"), 5))
+
+		var buf bytes.Buffer
+		assert.Nil(d.WriteDOT(&buf, nil))
+		assert.Equal(`digraph "dag" {
+	"test:a" [label="test:a"];
+	"test:b" [label="test:b"];
+	"test:c" [label="test:c"];
+	"test:a" -> "test:b" [label="1"];
+	"test:a" -> "test:c" [label="5"];
+	"test:b" -> "test:c" [label="2"];
+}
+`, buf.String())
+
+		buf.Reset()
+		assert.Nil(d.WriteDOT(&buf, &daggo.DOTOptions[daggo.Vertice]{
+			Name:          "perm",
+			ClusterByType: true,
+			Colors:        map[string]string{"test": "lightblue"},
+			Highlight:     d.Shortest(V("a"), V("c"), true),
+		}))
+		assert.Equal(`digraph "perm" {
+	subgraph "cluster_test" {
+		label="test";
+		"test:a" [label="test:a", style=filled, fillcolor="lightblue", color="red", penwidth=2];
+		"test:b" [label="test:b", style=filled, fillcolor="lightblue", color="red", penwidth=2];
+		"test:c" [label="test:c", style=filled, fillcolor="lightblue", color="red", penwidth=2];
+	}
+	"test:a" -> "test:b" [label="1", color="red", penwidth=2];
+	"test:a" -> "test:c" [label="5"];
+	"test:b" -> "test:c" [label="2", color="red", penwidth=2];
+}
+`, buf.String())
+	})
+
+	t.Run("Graph.WriteDOT should highlight typed vertices", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.NewGraph[T, int]()
+		assert.Nil(d.AddEdge(T{"user", "alice"}, T{"group", "dev"}, 1))
+		assert.Nil(d.AddEdge(T{"group", "dev"}, T{"role", "admin"}, 1))
+		assert.Nil(d.AddEdge(T{"user", "alice"}, T{"role", "admin"}, 5))
+
+		var buf bytes.Buffer
+		assert.Nil(d.WriteDOT(&buf, &daggo.DOTOptions[T]{
+			Highlight:      d.Shortest(T{"user", "alice"}, T{"role", "admin"}, true),
+			HighlightColor: "blue",
+		}))
+		assert.Equal(`digraph "dag" {
+	"group:dev" [label="group:dev", color="blue", penwidth=2];
+	"role:admin" [label="role:admin", color="blue", penwidth=2];
+	"user:alice" [label="user:alice", color="blue", penwidth=2];
+	"group:dev" -> "role:admin" [label="1", color="blue", penwidth=2];
+	"user:alice" -> "group:dev" [label="1", color="blue", penwidth=2];
+	"user:alice" -> "role:admin" [label="5"];
+}
+`, buf.String())
+	})
+
+	t.Run("ParseDOT", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		assert.Nil(d.AddEdge(V("a"), V("b"), 1))
+		assert.Nil(d.AddEdge(V("b"), V("c"), -2))
+		assert.Nil(d.AddEdge(V("a"), V("c"), 5))
+		assert.Nil(d.AddVertice(V(`x"y`)))
+
+		var buf bytes.Buffer
+		assert.Nil(d.WriteDOT(&buf, &daggo.DOTOptions[daggo.Vertice]{ClusterByType: true, Highlight: daggo.Vertices{V("a"), V("b")}}))
+		a, err := daggo.ParseDOT(&buf, func(ty, id string) (daggo.Vertice, error) {
+			if ty != "test" {
+				return nil, fmt.Errorf("unknown type %q", ty)
+			}
+			return V(id), nil
+		})
+		assert.Nil(err)
+		assert.True(d.Equal(a))
+	})
+
+	t.Run("ParseGraphDOT", func(t *testing.T) {
+		assert := assert.New(t)
+
+		src := `/* permissions */
+strict digraph G {
+	graph [rankdir=LR]
+	node [shape=box];
+	// users
+	alice; bob
+	subgraph cluster_0 {
+		label = "groups"
+		"group:dev" "group:ops"
+	}
+	alice -> "group:dev" -> "role:admin" [weight=3]
+	bob:port -> {"group:dev" "group:ops"} [label="2", color=blue]
+	"group:ops" -> "role:admin" [label="not a number"]
+}
+`
+		d, err := daggo.ParseGraphDOT[T, float64](strings.NewReader(src), newT)
+		assert.Nil(err)
+		assert.Equal(5, d.Len())
+		assert.Equal(daggo.List[T]{{"user", "alice"}, {"user", "bob"}}, d.StartingVertices().Sort())
+
+		x := daggo.NewGraph[T, float64]()
+		assert.Nil(x.AddEdge(T{"user", "alice"}, T{"group", "dev"}, 3))
+		assert.Nil(x.AddEdge(T{"group", "dev"}, T{"role", "admin"}, 3))
+		assert.Nil(x.AddEdge(T{"user", "bob"}, T{"group", "dev"}, 2))
+		assert.Nil(x.AddEdge(T{"user", "bob"}, T{"group", "ops"}, 2))
+		assert.Nil(x.AddEdge(T{"group", "ops"}, T{"role", "admin"}, 0))
+		assert.True(x.Equal(d))
+	})
+
+	t.Run("ParseDOT should return error if data invalid", func(t *testing.T) {
+		assert := assert.New(t)
+
+		fn := func(ty, id string) (daggo.Vertice, error) { return V(id), nil }
+		for _, src := range []string{
+			``,
+			`graph { a -- b }`,
+			`digraph { a -- b }`,
+			`digraph { a -> }`,
+			`digraph { a -> b [weight=x] }`,
+			`digraph { "a }`,
+			`digraph { a -> b } c`,
+			`digraph { a [label] }`,
+		} {
+			_, err := daggo.ParseDOT(strings.NewReader(src), fn)
+			assert.NotNil(err, src)
+		}
+
+		_, err := daggo.ParseDOT(strings.NewReader(`digraph { a -> b -> c -> a }`), fn)
+		var ce *daggo.ErrCycle
+		assert.True(errors.As(err, &ce))
+		assert.Equal([]string{"a", "b", "c", "a"}, ce.Path.IDs())
+
+		_, err = daggo.ParseDOT(strings.NewReader(`digraph { a -> a }`), fn)
+		assert.True(errors.Is(err, &daggo.ErrSelfLoop{}))
+		_, err = daggo.ParseDOT(strings.NewReader(`digraph { "" }`), fn)
+		assert.True(errors.Is(err, &daggo.ErrInvalidVertice{}))
+	})
+}
@@ -0,0 +1,88 @@
+package daggo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidVertice is returned when a vertice is nil or has an empty ID.
+// All errors in this package can be matched by type with errors.Is and errors.As,
+// e.g. errors.Is(err, &ErrInvalidVertice{}).
+type ErrInvalidVertice struct {
+	Vertice Vertice
+}
+
+func (e *ErrInvalidVertice) Error() string {
+	return fmt.Sprintf("invalid vertice: %#v", e.Vertice)
+}
+
+// Is reports whether the target is an *ErrInvalidVertice.
+func (e *ErrInvalidVertice) Is(target error) bool {
+	_, ok := target.(*ErrInvalidVertice)
+	return ok
+}
+
+// ErrSelfLoop is returned when connecting a vertice to itself.
+type ErrSelfLoop struct {
+	Vertice Vertice
+}
+
+func (e *ErrSelfLoop) Error() string {
+	return fmt.Sprintf("starting vertice is ending vertice: %s", verticeUID(e.Vertice))
+}
+
+// Is reports whether the target is an *ErrSelfLoop.
+func (e *ErrSelfLoop) Is(target error) bool {
+	_, ok := target.(*ErrSelfLoop)
+	return ok
+}
+
+// ErrDuplicateVertice is returned when adding a vertice that exists.
+type ErrDuplicateVertice struct {
+	Vertice Vertice
+}
+
+func (e *ErrDuplicateVertice) Error() string {
+	return fmt.Sprintf("vertice exists: %s", verticeUID(e.Vertice))
+}
+
+// Is reports whether the target is an *ErrDuplicateVertice.
+func (e *ErrDuplicateVertice) Is(target error) bool {
+	_, ok := target.(*ErrDuplicateVertice)
+	return ok
+}
+
+// ErrUnknownVertice is returned when a vertice UID ("type:id") is not found.
+type ErrUnknownVertice struct {
+	UID string
+}
+
+func (e *ErrUnknownVertice) Error() string {
+	return fmt.Sprintf("unknown vertice: %s", e.UID)
+}
+
+// Is reports whether the target is an *ErrUnknownVertice.
+func (e *ErrUnknownVertice) Is(target error) bool {
+	_, ok := target.(*ErrUnknownVertice)
+	return ok
+}
+
+// ErrCycle is returned when a cyclic graph will come into being.
+// Path is the offending cycle, its first vertice is also its last vertice.
+type ErrCycle struct {
+	Path Vertices
+}
+
+func (e *ErrCycle) Error() string {
+	uids := make([]string, len(e.Path))
+	for i, v := range e.Path {
+		uids[i] = verticeUID(v)
+	}
+	return fmt.Sprintf("cyclic graph will come into being: %s", strings.Join(uids, " -> "))
+}
+
+// Is reports whether the target is an *ErrCycle.
+func (e *ErrCycle) Is(target error) bool {
+	_, ok := target.(*ErrCycle)
+	return ok
+}
@@ -0,0 +1,81 @@
+package daggo_test
+
+import (
+	"errors"
+	"testing"
+
+	daggo "github.com/open-trust/dag-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrors(t *testing.T) {
+	t.Run("DAG.AddEdge", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		err := d.AddEdge(nil, V("a"), 0)
+		assert.True(errors.Is(err, &daggo.ErrInvalidVertice{}))
+		err = d.AddEdge(V("a"), V(""), 0)
+		assert.True(errors.Is(err, &daggo.ErrInvalidVertice{}))
+		var ie *daggo.ErrInvalidVertice
+		assert.True(errors.As(err, &ie))
+		assert.Equal(V(""), ie.Vertice)
+
+		err = d.AddEdge(V("a"), V("a"), 0)
+		assert.True(errors.Is(err, &daggo.ErrSelfLoop{}))
+		assert.False(errors.Is(err, &daggo.ErrCycle{}))
+		assert.Equal("starting vertice is ending vertice: test:a", err.Error())
+
+		assert.Nil(d.AddEdge(V("a"), V("b"), 0))
+		assert.Nil(d.AddEdge(V("b"), V("c"), 0))
+		assert.Nil(d.AddEdge(V("c"), V("d"), 0))
+		assert.Nil(d.AddEdge(V("x"), V("d"), 0))
+		err = d.AddEdge(V("d"), V("a"), 0)
+		assert.True(errors.Is(err, &daggo.ErrCycle{}))
+		var ce *daggo.ErrCycle
+		assert.True(errors.As(err, &ce))
+		assert.Equal(daggo.Vertices{V("d"), V("a"), V("b"), V("c"), V("d")}, ce.Path)
+		assert.Equal("cyclic graph will come into being: test:d -> test:a -> test:b -> test:c -> test:d", err.Error())
+	})
+
+	t.Run("DAG.AddVertice", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		assert.True(errors.Is(d.AddVertice(nil), &daggo.ErrInvalidVertice{}))
+		assert.Nil(d.AddVertice(V("a")))
+		err := d.AddVertice(V("a"))
+		var de *daggo.ErrDuplicateVertice
+		assert.True(errors.As(err, &de))
+		assert.Equal(V("a"), de.Vertice)
+		assert.Equal("vertice exists: test:a", err.Error())
+	})
+
+	t.Run("DAG.Merge", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		assert.Nil(d.AddEdge(V("a"), V("b"), 0))
+		a := daggo.New()
+		assert.Nil(a.AddEdge(V("b"), V("a"), 0))
+		err := d.Merge(a)
+		var ce *daggo.ErrCycle
+		assert.True(errors.As(err, &ce))
+		assert.Equal(3, len(ce.Path))
+		assert.Equal(ce.Path[0], ce.Path[2])
+	})
+
+	t.Run("FromJSON", func(t *testing.T) {
+		assert := assert.New(t)
+
+		j := &daggo.JSON{
+			Vertices: []daggo.Vertice{V("a")},
+			Edges:    map[string]map[string]int{"test:a": {"test:b": 1}},
+		}
+		_, err := daggo.FromJSON(j)
+		var ue *daggo.ErrUnknownVertice
+		assert.True(errors.As(err, &ue))
+		assert.Equal("test:b", ue.UID)
+		assert.Equal("unknown vertice: test:b", err.Error())
+	})
+}
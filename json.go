@@ -0,0 +1,188 @@
+package daggo
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// JSONVersion is the schema version of the JSON encoded DAG.
+const JSONVersion = 1
+
+type jsonGraph[W Weight] struct {
+	Version  int           `json:"version"`
+	Vertices []jsonVertice `json:"vertices"`
+	Edges    []jsonEdge[W] `json:"edges"`
+}
+
+type jsonVertice struct {
+	Type string          `json:"type"`
+	ID   string          `json:"id"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+type jsonEdge[W Weight] struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Weight W      `json:"weight"`
+}
+
+// VerticeFactory creates a vertice from its ID and JSON encoded data.
+type VerticeFactory func(id string, data json.RawMessage) (Vertice, error)
+
+// JSONFactory returns a VerticeFactory that decodes the JSON encoded data into a T.
+func JSONFactory[T Vertice]() VerticeFactory {
+	return func(id string, data json.RawMessage) (Vertice, error) {
+		var v T
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+}
+
+// Registry maps vertice types to factories, it is safe for concurrent use.
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[string]VerticeFactory
+}
+
+// NewRegistry returns a new Registry.
+func NewRegistry() *Registry {
+	return &Registry{factories: make(map[string]VerticeFactory)}
+}
+
+// DefaultRegistry is the Registry used by Graph.UnmarshalJSON.
+var DefaultRegistry = NewRegistry()
+
+// RegisterVertice registers a factory for the vertice type into the DefaultRegistry.
+func RegisterVertice(ty string, fn VerticeFactory) {
+	DefaultRegistry.Register(ty, fn)
+}
+
+// Register registers a factory for the vertice type, it replaces the registered one.
+func (r *Registry) Register(ty string, fn VerticeFactory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.factories[ty] = fn
+}
+
+// Vertice creates a vertice by the factory registered for the type.
+func (r *Registry) Vertice(ty, id string, data json.RawMessage) (Vertice, error) {
+	r.mu.RLock()
+	fn, ok := r.factories[ty]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unregistered vertice type: %q", ty)
+	}
+
+	v, err := fn(id, data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vertice %s:%s: %w", ty, id, err)
+	}
+	if v == nil || v.Type() != ty || v.ID() != id {
+		return nil, &ErrInvalidVertice{Vertice: v}
+	}
+	return v, nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// Vertices are encoded with their type, ID and JSON encoded data, and sorted by vertice UID.
+func (d *Graph[V, W]) MarshalJSON() ([]byte, error) {
+	d.rlock()
+	defer d.runlock()
+
+	keys := make([]string, 0, len(d.blocks))
+	for k := range d.blocks {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	j := jsonGraph[W]{
+		Version:  JSONVersion,
+		Vertices: make([]jsonVertice, 0, len(keys)),
+		Edges:    make([]jsonEdge[W], 0),
+	}
+	for _, k := range keys {
+		b := d.blocks[k]
+		data, err := json.Marshal(b.vertice)
+		if err != nil {
+			return nil, err
+		}
+		j.Vertices = append(j.Vertices, jsonVertice{Type: b.vertice.Type(), ID: b.vertice.ID(), Data: data})
+
+		nks := make([]string, 0, len(b.next))
+		for kk := range b.next {
+			nks = append(nks, kk)
+		}
+		sort.Strings(nks)
+		for _, kk := range nks {
+			j.Edges = append(j.Edges, jsonEdge[W]{From: k, To: kk, Weight: b.next[kk]})
+		}
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, vertices are created by the DefaultRegistry.
+func (d *Graph[V, W]) UnmarshalJSON(data []byte) error {
+	return d.UnmarshalJSONWith(data, DefaultRegistry)
+}
+
+// UnmarshalJSONWith replaces the DAG with the JSON encoded data, vertices are created by the registry.
+// The DAG is unchanged if error returned.
+func (d *Graph[V, W]) UnmarshalJSONWith(data []byte, r *Registry) error {
+	var j jsonGraph[W]
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if j.Version != JSONVersion {
+		return fmt.Errorf("unsupported JSON version: %d", j.Version)
+	}
+
+	nd := NewGraph[V, W]()
+	for _, jv := range j.Vertices {
+		x, err := r.Vertice(jv.Type, jv.ID, jv.Data)
+		if err != nil {
+			return err
+		}
+		v, ok := x.(V)
+		if !ok {
+			return fmt.Errorf("invalid vertice %s:%s: %T is not %T", jv.Type, jv.ID, x, v)
+		}
+		k := verticeUID(v)
+		if _, ok := nd.blocks[k]; ok {
+			return &ErrDuplicateVertice{Vertice: v}
+		}
+		nd.blocks[k] = &block[V, W]{
+			vertice: v,
+			prev:    make(map[string]W),
+			next:    make(map[string]W),
+		}
+	}
+	for _, e := range j.Edges {
+		startBlock, ok := nd.blocks[e.From]
+		if !ok {
+			return &ErrUnknownVertice{UID: e.From}
+		}
+		endBlock, ok := nd.blocks[e.To]
+		if !ok {
+			return &ErrUnknownVertice{UID: e.To}
+		}
+		if startBlock == endBlock {
+			return &ErrSelfLoop{Vertice: startBlock.vertice}
+		}
+		startBlock.next[e.To] = e.Weight
+		endBlock.prev[e.From] = e.Weight
+	}
+	if cycle := nd.cycleKeys(); cycle != nil {
+		return nd.cycleError(cycle)
+	}
+
+	d.lock()
+	defer d.unlock()
+
+	d.setBlocks(nd.blocks)
+	return nil
+}
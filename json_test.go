@@ -0,0 +1,86 @@
+package daggo_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	daggo "github.com/open-trust/dag-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	daggo.RegisterVertice("test", daggo.JSONFactory[V]())
+}
+
+func TestJSON(t *testing.T) {
+	t.Run("DAG.MarshalJSON & UnmarshalJSON", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		assert.Nil(d.AddEdge(V("a"), V("b"), 1))
+		assert.Nil(d.AddEdge(V("a"), V("c"), 2))
+		assert.Nil(d.AddEdge(V("b"), V("c"), 3))
+		assert.Nil(d.AddVertice(V("x")))
+
+		data, err := json.Marshal(d)
+		assert.Nil(err)
+		assert.Equal(`{"version":1,"vertices":[{"type":"test","id":"a","data":"a"},{"type":"test","id":"b","data":"b"},{"type":"test","id":"c","data":"c"},{"type":"test","id":"x","data":"x"}],"edges":[{"from":"test:a","to":"test:b","weight":1},{"from":"test:a","to":"test:c","weight":2},{"from":"test:b","to":"test:c","weight":3}]}`, string(data))
+
+		a := daggo.New()
+		assert.Nil(json.Unmarshal(data, a))
+		assert.True(d.Equal(a))
+
+		var x daggo.DAG
+		assert.Nil(json.Unmarshal(data, &x))
+		assert.True(d.Equal(&x))
+
+		s := daggo.NewSync()
+		assert.Nil(json.Unmarshal(data, s))
+		assert.True(d.Equal(s))
+	})
+
+	t.Run("Graph.UnmarshalJSONWith", func(t *testing.T) {
+		assert := assert.New(t)
+
+		r := daggo.NewRegistry()
+		r.Register("user", daggo.JSONFactory[T]())
+		r.Register("group", func(id string, data json.RawMessage) (daggo.Vertice, error) {
+			return T{"group", id}, nil
+		})
+
+		d := daggo.NewGraph[T, time.Duration]()
+		assert.Nil(d.AddEdge(T{"user", "a"}, T{"group", "g"}, time.Second))
+		data, err := json.Marshal(d)
+		assert.Nil(err)
+
+		a := daggo.NewGraph[T, time.Duration]()
+		assert.Nil(a.UnmarshalJSONWith(data, r))
+		assert.True(d.Equal(a))
+		assert.Equal(daggo.List[T]{{"user", "a"}}, a.Vertices("user"))
+
+		// vertices of type "test" are not T
+		b := daggo.NewGraph[T, time.Duration]()
+		assert.NotNil(b.UnmarshalJSONWith([]byte(`{"version":1,"vertices":[{"type":"test","id":"a","data":"a"}]}`), daggo.DefaultRegistry))
+		assert.NotNil(a.UnmarshalJSON(data))
+		assert.True(d.Equal(a))
+	})
+
+	t.Run("should return error if data invalid", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		assert.Nil(d.AddEdge(V("a"), V("b"), 1))
+		x := d.Clone()
+
+		assert.NotNil(json.Unmarshal([]byte(`{"version":2}`), d))
+		assert.NotNil(json.Unmarshal([]byte(`{"version":1,"vertices":[{"type":"none","id":"a"}]}`), d))
+		assert.NotNil(json.Unmarshal([]byte(`{"version":1,"vertices":[{"type":"test","id":"a","data":"b"}]}`), d))
+		assert.True(errors.Is(json.Unmarshal([]byte(`{"version":1,"vertices":[{"type":"test","id":"a","data":"a"},{"type":"test","id":"a","data":"a"}]}`), d), &daggo.ErrDuplicateVertice{}))
+		assert.True(errors.Is(json.Unmarshal([]byte(`{"version":1,"vertices":[{"type":"test","id":"a","data":"a"}],"edges":[{"from":"test:a","to":"test:b"}]}`), d), &daggo.ErrUnknownVertice{}))
+		assert.True(errors.Is(json.Unmarshal([]byte(`{"version":1,"vertices":[{"type":"test","id":"a","data":"a"}],"edges":[{"from":"test:a","to":"test:a"}]}`), d), &daggo.ErrSelfLoop{}))
+		assert.True(errors.Is(json.Unmarshal([]byte(`{"version":1,"vertices":[{"type":"test","id":"a","data":"a"},{"type":"test","id":"b","data":"b"}],"edges":[{"from":"test:a","to":"test:b"},{"from":"test:b","to":"test:a"}]}`), d), &daggo.ErrCycle{}))
+		assert.True(x.Equal(d))
+	})
+}
@@ -0,0 +1,100 @@
+package daggo
+
+// LowestCommonAncestors returns the common ancestors of the vertices that no other common ancestor is reachable from,
+// sorted by UID. A vertice is an ancestor of itself.
+// It uses the reachability index if attached.
+func (d *Graph[V, W]) LowestCommonAncestors(vs ...V) List[V] {
+	return d.LowestCommonAncestorsOfType("", vs...)
+}
+
+// LowestCommonAncestorsOfType is the same as LowestCommonAncestors, but only the common ancestors of type ty are considered.
+// An empty ty means all types.
+func (d *Graph[V, W]) LowestCommonAncestorsOfType(ty string, vs ...V) List[V] {
+	d.rlock()
+	defer d.runlock()
+
+	common := d.commonKeys(vs, true)
+	if len(common) == 0 {
+		return make([]V, 0)
+	}
+	match := func(k string) bool {
+		return common[k] && (ty == "" || d.blocks[k].vertice.Type() == ty)
+	}
+
+	keys := make([]string, 0, len(common))
+	if d.index != nil {
+		for k := range common {
+			if match(k) {
+				keys = append(keys, k)
+			}
+		}
+		return d.keysToList(d.index.lowest(keys)).Sort()
+	}
+
+	// below[k] reports whether a matched common ancestor is reachable from k,
+	// the paths from k to it only go through common ancestors.
+	// The common ancestors are among the ancestors of the first vertice, walk them in reverse topological order.
+	below := make(map[string]bool, len(common))
+	order := d.reachOrder(verticeUID(vs[0]), true)
+	for i := len(order) - 1; i >= 0; i-- {
+		k := order[i]
+		if !common[k] {
+			continue
+		}
+		for kk := range d.blocks[k].next {
+			if match(kk) || below[kk] {
+				below[k] = true
+				break
+			}
+		}
+		if match(k) && !below[k] {
+			keys = append(keys, k)
+		}
+	}
+	return d.keysToList(keys).Sort()
+}
+
+// CommonDescendants returns the common descendants of the vertices sorted by UID. A vertice is a descendant of itself.
+// It uses the reachability index if attached.
+func (d *Graph[V, W]) CommonDescendants(vs ...V) List[V] {
+	return d.CommonDescendantsOfType("", vs...)
+}
+
+// CommonDescendantsOfType is the same as CommonDescendants, but only returns the vertices of type ty.
+// An empty ty means all types.
+func (d *Graph[V, W]) CommonDescendantsOfType(ty string, vs ...V) List[V] {
+	d.rlock()
+	defer d.runlock()
+
+	keys := make([]string, 0)
+	for k := range d.commonKeys(vs, false) {
+		if ty == "" || d.blocks[k].vertice.Type() == ty {
+			keys = append(keys, k)
+		}
+	}
+	return d.keysToList(keys).Sort()
+}
+
+// commonKeys returns the keys of the common ancestors (inclusive) of the vertices,
+// or the common descendants if ancestors is false.
+func (d *Graph[V, W]) commonKeys(vs []V, ancestors bool) map[string]bool {
+	keys := d.existingKeys(vs)
+	if len(keys) == 0 || len(keys) != len(vs) {
+		return map[string]bool{}
+	}
+
+	if d.index != nil {
+		return d.index.common(keys, ancestors)
+	}
+
+	common := d.reachSet(keys[:1], ancestors)
+	for _, k := range keys[1:] {
+		set := d.reachSet([]string{k}, ancestors)
+		for kk := range common {
+			if !set[kk] {
+				delete(common, kk)
+			}
+		}
+	}
+	return common
+}
@@ -0,0 +1,128 @@
+package daggo_test
+
+import (
+	"fmt"
+	"testing"
+
+	daggo "github.com/open-trust/dag-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommon(t *testing.T) {
+	t.Run("Graph.LowestCommonAncestors", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.NewGraph[T, int]()
+		assert.Nil(d.AddEdge(T{"org", "root"}, T{"group", "eng"}, 1))
+		assert.Nil(d.AddEdge(T{"org", "root"}, T{"group", "ops"}, 1))
+		assert.Nil(d.AddEdge(T{"group", "eng"}, T{"team", "backend"}, 1))
+		assert.Nil(d.AddEdge(T{"group", "eng"}, T{"team", "frontend"}, 1))
+		assert.Nil(d.AddEdge(T{"team", "backend"}, T{"user", "alice"}, 1))
+		assert.Nil(d.AddEdge(T{"team", "backend"}, T{"user", "carol"}, 1))
+		assert.Nil(d.AddEdge(T{"team", "frontend"}, T{"user", "bob"}, 1))
+		assert.Nil(d.AddEdge(T{"group", "ops"}, T{"user", "carol"}, 1))
+		assert.Nil(d.AddEdge(T{"group", "ops"}, T{"user", "dave"}, 1))
+		assert.Nil(d.AddEdge(T{"user", "alice"}, T{"role", "admin"}, 1))
+		assert.Nil(d.AddEdge(T{"user", "carol"}, T{"role", "admin"}, 1))
+		assert.Nil(d.AddEdge(T{"role", "admin"}, T{"resource", "db"}, 1))
+		assert.Nil(d.AddEdge(T{"user", "carol"}, T{"resource", "db"}, 1))
+		assert.Nil(d.AddVertice(T{"user", "eve"}))
+
+		x := d.Clone()
+		x.ReachIndex()
+		for _, d := range []*daggo.Graph[T, int]{d, x} {
+			alice, bob, carol, dave := T{"user", "alice"}, T{"user", "bob"}, T{"user", "carol"}, T{"user", "dave"}
+			assert.Equal([]string{"backend"}, d.LowestCommonAncestors(alice, carol).IDs())
+			assert.Equal([]string{"eng"}, d.LowestCommonAncestors(alice, bob).IDs())
+			assert.Equal([]string{"ops"}, d.LowestCommonAncestors(carol, dave).IDs())
+			assert.Equal([]string{"root"}, d.LowestCommonAncestors(alice, bob, dave).IDs())
+			assert.Equal([]string{"admin"}, d.LowestCommonAncestors(T{"role", "admin"}, T{"resource", "db"}).IDs())
+			assert.Equal([]string{"alice", "carol"}, d.LowestCommonAncestorsOfType("user", T{"role", "admin"}, T{"resource", "db"}).IDs())
+			assert.Equal([]string{"carol"}, d.LowestCommonAncestors(carol).IDs())
+			assert.Equal([]string{"carol"}, d.LowestCommonAncestors(carol, T{"role", "admin"}).IDs())
+
+			assert.Equal([]string{"eng"}, d.LowestCommonAncestorsOfType("group", alice, carol).IDs())
+			assert.Equal([]string{"root"}, d.LowestCommonAncestorsOfType("org", alice, carol).IDs())
+			assert.Equal([]string{"eng", "ops"}, d.LowestCommonAncestorsOfType("group", T{"role", "admin"}, T{"resource", "db"}).IDs())
+			assert.Equal([]string{}, d.LowestCommonAncestorsOfType("role", alice, carol).IDs())
+
+			assert.Equal([]string{}, d.LowestCommonAncestors(alice, T{"user", "eve"}).IDs())
+			assert.Equal([]string{}, d.LowestCommonAncestors(alice, T{"user", "none"}).IDs())
+			assert.Equal([]string{}, d.LowestCommonAncestors().IDs())
+		}
+	})
+
+	t.Run("Graph.CommonDescendants", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.NewGraph[T, int]()
+		assert.Nil(d.AddEdge(T{"org", "root"}, T{"group", "eng"}, 1))
+		assert.Nil(d.AddEdge(T{"org", "root"}, T{"group", "ops"}, 1))
+		assert.Nil(d.AddEdge(T{"group", "eng"}, T{"team", "backend"}, 1))
+		assert.Nil(d.AddEdge(T{"group", "eng"}, T{"team", "frontend"}, 1))
+		assert.Nil(d.AddEdge(T{"team", "backend"}, T{"user", "alice"}, 1))
+		assert.Nil(d.AddEdge(T{"team", "backend"}, T{"user", "carol"}, 1))
+		assert.Nil(d.AddEdge(T{"team", "frontend"}, T{"user", "bob"}, 1))
+		assert.Nil(d.AddEdge(T{"group", "ops"}, T{"user", "carol"}, 1))
+		assert.Nil(d.AddEdge(T{"group", "ops"}, T{"user", "dave"}, 1))
+		assert.Nil(d.AddEdge(T{"user", "alice"}, T{"role", "admin"}, 1))
+		assert.Nil(d.AddEdge(T{"user", "carol"}, T{"role", "admin"}, 1))
+		assert.Nil(d.AddEdge(T{"role", "admin"}, T{"resource", "db"}, 1))
+		assert.Nil(d.AddEdge(T{"user", "carol"}, T{"resource", "db"}, 1))
+		assert.Nil(d.AddVertice(T{"user", "eve"}))
+
+		x := d.Clone()
+		x.ReachIndex()
+		for _, d := range []*daggo.Graph[T, int]{d, x} {
+			backend, ops := T{"team", "backend"}, T{"group", "ops"}
+			assert.Equal([]string{"db", "admin", "carol"}, d.CommonDescendants(backend, ops).IDs())
+			assert.Equal([]string{"carol"}, d.CommonDescendantsOfType("user", backend, ops).IDs())
+			assert.Equal([]string{"db", "admin"}, d.CommonDescendants(T{"user", "alice"}, T{"user", "carol"}).IDs())
+			assert.Equal([]string{"db"}, d.CommonDescendants(T{"resource", "db"}).IDs())
+			assert.Equal([]string{}, d.CommonDescendants(T{"team", "frontend"}, ops).IDs())
+			assert.Equal([]string{}, d.CommonDescendants(T{"group", "none"}, ops).IDs())
+		}
+	})
+
+	t.Run("should work with vertices added after the reachability index", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		assert.Nil(d.AddEdge(V("a"), V("b"), 1))
+		assert.Nil(d.AddEdge(V("a"), V("c"), 1))
+		d.ReachIndex()
+		assert.Nil(d.AddVertice(V("z")))
+
+		assert.Equal([]string{"z"}, d.LowestCommonAncestors(V("z")).IDs())
+		assert.Equal([]string{"z"}, d.LowestCommonAncestors(V("z"), V("z")).IDs())
+		assert.Equal([]string{}, d.LowestCommonAncestors(V("b"), V("z")).IDs())
+		assert.Equal([]string{"z"}, d.CommonDescendants(V("z")).IDs())
+		assert.Equal([]string{}, d.CommonDescendants(V("a"), V("z")).IDs())
+		assert.Equal([]string{"a"}, d.LowestCommonAncestors(V("b"), V("c")).IDs())
+	})
+
+	t.Run("should answer from the reachability index without traversing ancestors", func(t *testing.T) {
+		assert := assert.New(t)
+
+		n := 1000
+		d := daggo.New()
+		for i := 1; i < n; i++ {
+			assert.Nil(d.AddEdge(V(fmt.Sprint(i-1)), V(fmt.Sprint(i)), 1))
+		}
+		assert.Nil(d.AddEdge(V(fmt.Sprint(n-1)), V("a"), 1))
+		assert.Nil(d.AddEdge(V(fmt.Sprint(n-1)), V("b"), 1))
+		x := d.Clone()
+		x.ReachIndex()
+
+		assert.Equal([]string{fmt.Sprint(n - 1)}, d.LowestCommonAncestors(V("a"), V("b")).IDs())
+		assert.Equal([]string{fmt.Sprint(n - 1)}, x.LowestCommonAncestors(V("a"), V("b")).IDs())
+		allocs := func(d *daggo.DAG) float64 {
+			return testing.AllocsPerRun(10, func() {
+				d.LowestCommonAncestors(V("a"), V("b"))
+			})
+		}
+		// searching allocates for every ancestor, the index does not
+		assert.Greater(allocs(d), float64(n))
+		assert.Less(allocs(x), float64(n/10))
+	})
+}
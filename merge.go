@@ -0,0 +1,109 @@
+package daggo
+
+import (
+	"sort"
+)
+
+// MergePolicy decides the weight of a connecting that exists in both DAG when merging.
+type MergePolicy int
+
+const (
+	// MergeOverwrite uses the weight in the other DAG.
+	MergeOverwrite MergePolicy = iota
+	// MergeKeep keeps the weight in the DAG.
+	MergeKeep
+	// MergeSum uses the sum of the two weights.
+	MergeSum
+	// MergeMin uses the minimum of the two weights.
+	MergeMin
+	// MergeMax uses the maximum of the two weights.
+	MergeMax
+)
+
+// MergeConflict is a connecting that exists in both DAG when merging.
+type MergeConflict[V Vertice, W Weight] struct {
+	From   V
+	To     V
+	Weight W // the weight in the DAG
+	Other  W // the weight in the other DAG
+	Merged W // the weight after merged
+}
+
+// MergeWith merge the other DAG into the DAG with the policy, returns the conflicts sorted by vertices UID.
+// It returns ErrCycle if cyclic graph will come into being, the merge is atomic, the DAG is unchanged if error returned.
+func (d *Graph[V, W]) MergeWith(a *Graph[V, W], policy MergePolicy) ([]MergeConflict[V, W], error) {
+	// take a snapshot so that the two DAG are never locked at the same time.
+	a = a.Clone()
+	d.lock()
+	defer d.unlock()
+
+	nd := &Graph[V, W]{blocks: make(map[string]*block[V, W], len(d.blocks)+len(a.blocks))}
+	for k, b := range d.blocks {
+		nd.blocks[k] = b.clone()
+	}
+	keys := make([]string, 0, len(a.blocks))
+	for k, x := range a.blocks {
+		keys = append(keys, k)
+		if _, ok := nd.blocks[k]; !ok {
+			nd.blocks[k] = &block[V, W]{
+				vertice: x.vertice,
+				prev:    make(map[string]W),
+				next:    make(map[string]W),
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	conflicts := make([]MergeConflict[V, W], 0)
+	for _, k := range keys {
+		x := a.blocks[k]
+		b := nd.blocks[k]
+		nks := make([]string, 0, len(x.next))
+		for kk := range x.next {
+			nks = append(nks, kk)
+		}
+		sort.Strings(nks)
+		for _, kk := range nks {
+			w := x.next[kk]
+			if bw, ok := b.next[kk]; ok {
+				w = mergeWeight(policy, bw, w)
+				conflicts = append(conflicts, MergeConflict[V, W]{
+					From:   b.vertice,
+					To:     nd.blocks[kk].vertice,
+					Weight: bw,
+					Other:  x.next[kk],
+					Merged: w,
+				})
+			}
+			b.next[kk] = w
+			nd.blocks[kk].prev[k] = w
+		}
+	}
+
+	if cycle := nd.cycleKeys(); cycle != nil {
+		return nil, nd.cycleError(cycle)
+	}
+	d.setBlocks(nd.blocks)
+	return conflicts, nil
+}
+
+func mergeWeight[W Weight](policy MergePolicy, w, other W) W {
+	switch policy {
+	case MergeKeep:
+		return w
+	case MergeSum:
+		return w + other
+	case MergeMin:
+		if other < w {
+			return other
+		}
+		return w
+	case MergeMax:
+		if other > w {
+			return other
+		}
+		return w
+	default:
+		return other
+	}
+}
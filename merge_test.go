@@ -0,0 +1,89 @@
+package daggo_test
+
+import (
+	"errors"
+	"testing"
+
+	daggo "github.com/open-trust/dag-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	t.Run("DAG.Merge should be atomic", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		assert.Nil(d.AddEdge(V("a"), V("b"), 1))
+		assert.Nil(d.AddEdge(V("b"), V("c"), 1))
+		x := d.Clone()
+
+		a := daggo.New()
+		assert.Nil(a.AddEdge(V("a"), V("x"), 1))
+		assert.Nil(a.AddEdge(V("a"), V("b"), 2))
+		assert.Nil(a.AddEdge(V("c"), V("y"), 1))
+		assert.Nil(a.AddEdge(V("y"), V("a"), 1))
+		err := d.Merge(a)
+		var ce *daggo.ErrCycle
+		assert.True(errors.As(err, &ce))
+		assert.Equal([]string{"a", "b", "c", "y", "a"}, ce.Path.IDs())
+		assert.True(x.Equal(d))
+	})
+
+	t.Run("DAG.Merge should connect new vertices", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		assert.Nil(d.AddEdge(V("a"), V("b"), 1))
+		a := daggo.New()
+		assert.Nil(a.AddEdge(V("x"), V("b"), 1))
+		assert.Nil(a.AddEdge(V("b"), V("y"), 1))
+		assert.Nil(d.Merge(a))
+
+		assert.Equal(daggo.Vertices{V("a"), V("x")}, d.FromVertices(V("b")).Sort())
+		assert.Equal(daggo.Vertices{V("y")}, d.ToVertices(V("b")))
+		assert.Equal(daggo.Vertices{V("a"), V("x")}, d.StartingVertices().Sort())
+		assert.NotNil(d.AddEdge(V("y"), V("x"), 1))
+	})
+
+	t.Run("DAG.MergeWith", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		assert.Nil(d.AddEdge(V("a"), V("b"), 1))
+		assert.Nil(d.AddEdge(V("a"), V("c"), 5))
+		a := daggo.New()
+		assert.Nil(a.AddEdge(V("a"), V("b"), 3))
+		assert.Nil(a.AddEdge(V("a"), V("c"), 2))
+		assert.Nil(a.AddEdge(V("b"), V("c"), 1))
+
+		cases := []struct {
+			policy daggo.MergePolicy
+			ab, ac int
+		}{
+			{daggo.MergeOverwrite, 3, 2},
+			{daggo.MergeKeep, 1, 5},
+			{daggo.MergeSum, 4, 7},
+			{daggo.MergeMin, 1, 2},
+			{daggo.MergeMax, 3, 5},
+		}
+		for _, c := range cases {
+			x := d.Clone()
+			conflicts, err := x.MergeWith(a, c.policy)
+			assert.Nil(err)
+			assert.Equal([]daggo.MergeConflict[daggo.Vertice, int]{
+				{From: V("a"), To: V("b"), Weight: 1, Other: 3, Merged: c.ab},
+				{From: V("a"), To: V("c"), Weight: 5, Other: 2, Merged: c.ac},
+			}, conflicts)
+
+			y := daggo.New()
+			assert.Nil(y.AddEdge(V("a"), V("b"), c.ab))
+			assert.Nil(y.AddEdge(V("a"), V("c"), c.ac))
+			assert.Nil(y.AddEdge(V("b"), V("c"), 1))
+			assert.True(y.Equal(x))
+		}
+
+		conflicts, err := d.MergeWith(daggo.New(), daggo.MergeSum)
+		assert.Nil(err)
+		assert.Equal(0, len(conflicts))
+	})
+}
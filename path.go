@@ -0,0 +1,522 @@
+package daggo
+
+import (
+	"math/big"
+	"sort"
+)
+
+// Path is a path of the Graph with the weights of its edges.
+type Path[V Vertice, W Weight] struct {
+	Vertices List[V]
+	Weights  []W // Weights[i] is the weight of the edge from Vertices[i] to Vertices[i+1]
+	Total    W
+}
+
+// End returns the last vertice of the path.
+func (p *Path[V, W]) End() V {
+	return p.Vertices[len(p.Vertices)-1]
+}
+
+// ShortestPath returns a shortest path from start to end, returns nil if end is not reachable from start.
+// The path length is the total weight if withWeight is true, otherwise the number of edges.
+// If several paths tie, the one through the previous vertice with the smallest UID is returned.
+func (d *Graph[V, W]) ShortestPath(start, end V, withWeight bool) *Path[V, W] {
+	d.rlock()
+	defer d.runlock()
+
+	return d.bestPath(start, end, withWeight, false)
+}
+
+// LongestPath returns a longest path from start to end, returns nil if end is not reachable from start.
+// The path length is the total weight if withWeight is true, otherwise the number of edges.
+// If several paths tie, the one through the previous vertice with the smallest UID is returned.
+func (d *Graph[V, W]) LongestPath(start, end V, withWeight bool) *Path[V, W] {
+	d.rlock()
+	defer d.runlock()
+
+	return d.bestPath(start, end, withWeight, true)
+}
+
+// ShortestPaths returns the shortest paths from start to every vertice reachable from it, sorted by the end vertices.
+func (d *Graph[V, W]) ShortestPaths(start V, withWeight bool) []*Path[V, W] {
+	d.rlock()
+	defer d.runlock()
+
+	return d.bestPaths(start, withWeight, false)
+}
+
+// LongestPaths returns the longest paths from start to every vertice reachable from it, sorted by the end vertices.
+func (d *Graph[V, W]) LongestPaths(start V, withWeight bool) []*Path[V, W] {
+	d.rlock()
+	defer d.runlock()
+
+	return d.bestPaths(start, withWeight, true)
+}
+
+// AllShortestPaths returns all the shortest paths from start to end, sorted by the UIDs of their vertices.
+func (d *Graph[V, W]) AllShortestPaths(start, end V, withWeight bool) []*Path[V, W] {
+	d.rlock()
+	defer d.runlock()
+
+	return d.tiedPaths(start, end, withWeight, false)
+}
+
+// AllLongestPaths returns all the longest paths from start to end, sorted by the UIDs of their vertices.
+func (d *Graph[V, W]) AllLongestPaths(start, end V, withWeight bool) []*Path[V, W] {
+	d.rlock()
+	defer d.runlock()
+
+	return d.tiedPaths(start, end, withWeight, true)
+}
+
+// KShortestPaths returns at most k shortest paths from start to end in increasing order of length,
+// paths of the same length are sorted by the UIDs of their vertices.
+// The path length is the total weight if withWeight is true, otherwise the number of edges.
+func (d *Graph[V, W]) KShortestPaths(start, end V, k int, withWeight bool) []*Path[V, W] {
+	d.rlock()
+	defer d.runlock()
+
+	res := make([]*Path[V, W], 0)
+	if k <= 0 || isNilVertice(start) || isNilVertice(end) {
+		return res
+	}
+	startKey, endKey := verticeUID(start), verticeUID(end)
+	if startKey == endKey {
+		return res
+	}
+	if _, ok := d.blocks[endKey]; !ok {
+		return res
+	}
+
+	// best[v] is the k shortest paths from v to end, a path is linked by its next vertice and the index in best[next].
+	type suffix struct {
+		state *pathState[W]
+		next  string
+		index int
+	}
+	order := d.reachOrder(startKey, false)
+	best := make(map[string][]*suffix, len(order))
+	for i := len(order) - 1; i >= 0; i-- {
+		key := order[i]
+		if key == endKey {
+			best[key] = []*suffix{{state: &pathState[W]{}}}
+			continue
+		}
+		b := d.blocks[key]
+		cands := make([]*suffix, 0)
+		for kk, w := range b.next {
+			for j, s := range best[kk] {
+				cands = append(cands, &suffix{
+					state: &pathState[W]{weight: s.state.weight + w, hops: s.state.hops + 1},
+					next:  kk,
+					index: j,
+				})
+			}
+		}
+		sort.Slice(cands, func(i, j int) bool {
+			if c := compareState(cands[i].state, cands[j].state, withWeight, false); c != 0 {
+				return c < 0
+			}
+			if cands[i].next != cands[j].next {
+				return cands[i].next < cands[j].next
+			}
+			return cands[i].index < cands[j].index
+		})
+		if len(cands) > k {
+			cands = cands[:k]
+		}
+		best[key] = cands
+	}
+
+	for _, s := range best[startKey] {
+		keys := []string{startKey}
+		for s.next != "" {
+			keys = append(keys, s.next)
+			s = best[s.next][s.index]
+		}
+		res = append(res, d.keysToPath(keys))
+	}
+	return res
+}
+
+// Shortest find a shortest paths.
+func (d *Graph[V, W]) Shortest(start, end V, withWeight bool) List[V] {
+	if p := d.ShortestPath(start, end, withWeight); p != nil {
+		return p.Vertices
+	}
+	return make([]V, 0)
+}
+
+// Longest find a longest paths.
+func (d *Graph[V, W]) Longest(start, end V, withWeight bool) List[V] {
+	if p := d.LongestPath(start, end, withWeight); p != nil {
+		return p.Vertices
+	}
+	return make([]V, 0)
+}
+
+// CountPaths returns the number of paths from start to end.
+func (d *Graph[V, W]) CountPaths(start, end V) *big.Int {
+	d.rlock()
+	defer d.runlock()
+
+	n := new(big.Int)
+	if isNilVertice(start) || isNilVertice(end) {
+		return n
+	}
+	startKey, endKey := verticeUID(start), verticeUID(end)
+	if startKey == endKey {
+		return n
+	}
+	counts := make(map[string]*big.Int)
+	for _, k := range d.reachOrder(startKey, false) {
+		c := counts[k]
+		if k == startKey {
+			c = big.NewInt(1)
+		}
+		if k == endKey {
+			return n.Set(c)
+		}
+		for kk := range d.blocks[k].next {
+			if counts[kk] == nil {
+				counts[kk] = new(big.Int)
+			}
+			counts[kk].Add(counts[kk], c)
+		}
+	}
+	return n
+}
+
+// CountPathsTo returns the number of paths from all starting vertices to v, a starting vertice itself counts one path.
+func (d *Graph[V, W]) CountPathsTo(v V) *big.Int {
+	d.rlock()
+	defer d.runlock()
+
+	n := new(big.Int)
+	if isNilVertice(v) {
+		return n
+	}
+	counts := make(map[string]*big.Int)
+	for _, k := range d.reachOrder(verticeUID(v), true) {
+		b := d.blocks[k]
+		c := new(big.Int)
+		if len(b.prev) == 0 {
+			c.SetInt64(1)
+		}
+		for kk := range b.prev {
+			c.Add(c, counts[kk])
+		}
+		counts[k] = c
+		n = c
+	}
+	return n
+}
+
+// PathOptions is the options of Graph.Paths.
+type PathOptions struct {
+	// MaxDepth is the max number of edges of a path, zero means unlimited.
+	MaxDepth int
+	// MaxCount is the max number of paths, zero means unlimited.
+	MaxCount int
+	// Types are the vertice types that paths can go through, empty means all types.
+	// The start and end vertices are not filtered.
+	Types []string
+}
+
+// Paths calls fn for every path from start to end in lexicographical order of the vertices UIDs,
+// until fn returns false. The opts can be nil.
+// The fn is called with the read lock held, it should not call any method of the DAG,
+// otherwise it may deadlock a DAG created by NewSync.
+func (d *Graph[V, W]) Paths(start, end V, opts *PathOptions, fn func(p *Path[V, W]) bool) {
+	if opts == nil {
+		opts = &PathOptions{}
+	}
+
+	d.rlock()
+	defer d.runlock()
+
+	if isNilVertice(start) || isNilVertice(end) {
+		return
+	}
+	startKey, endKey := verticeUID(start), verticeUID(end)
+	if _, ok := d.blocks[startKey]; !ok || startKey == endKey {
+		return
+	}
+	if _, ok := d.blocks[endKey]; !ok {
+		return
+	}
+	var types map[string]bool
+	if len(opts.Types) > 0 {
+		types = make(map[string]bool, len(opts.Types))
+		for _, ty := range opts.Types {
+			types[ty] = true
+		}
+	}
+
+	// hops[v] is the min number of edges from v to end through the allowed vertices.
+	hops := map[string]int{endKey: 0}
+	queue := []string{endKey}
+	for len(queue) > 0 {
+		k := queue[0]
+		queue = queue[1:]
+		for kk := range d.blocks[k].prev {
+			if _, ok := hops[kk]; ok {
+				continue
+			}
+			if kk != startKey && types != nil && !types[d.blocks[kk].vertice.Type()] {
+				continue
+			}
+			hops[kk] = hops[k] + 1
+			queue = append(queue, kk)
+		}
+	}
+	if _, ok := hops[startKey]; !ok {
+		return
+	}
+
+	type frame struct {
+		key  string
+		next []string
+	}
+	count := 0
+	keys := []string{startKey}
+	stack := []*frame{{key: startKey, next: sortedKeys(d.blocks[startKey].next)}}
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		if len(f.next) == 0 {
+			stack = stack[:len(stack)-1]
+			keys = keys[:len(keys)-1]
+			continue
+		}
+		k := f.next[0]
+		f.next = f.next[1:]
+		h, ok := hops[k]
+		if !ok || (opts.MaxDepth > 0 && len(keys)+h > opts.MaxDepth) {
+			continue
+		}
+		if k == endKey {
+			count++
+			if !fn(d.keysToPath(append(keys, k))) || (opts.MaxCount > 0 && count >= opts.MaxCount) {
+				return
+			}
+			continue
+		}
+		keys = append(keys, k)
+		stack = append(stack, &frame{key: k, next: sortedKeys(d.blocks[k].next)})
+	}
+}
+
+// pathState is the best known path from the source to a vertice.
+type pathState[W Weight] struct {
+	prev   string
+	weight W
+	hops   int
+}
+
+func (d *Graph[V, W]) bestPath(start, end V, withWeight, longest bool) *Path[V, W] {
+	if isNilVertice(start) || isNilVertice(end) {
+		return nil
+	}
+	endKey := verticeUID(end)
+	if _, ok := d.blocks[endKey]; !ok {
+		return nil
+	}
+	startKey := verticeUID(start)
+	states := d.relax(startKey, withWeight, longest)
+	if _, ok := states[endKey]; !ok || endKey == startKey {
+		return nil
+	}
+	return d.buildPath(endKey, states)
+}
+
+func (d *Graph[V, W]) bestPaths(start V, withWeight, longest bool) []*Path[V, W] {
+	res := make([]*Path[V, W], 0)
+	if isNilVertice(start) {
+		return res
+	}
+	startKey := verticeUID(start)
+	states := d.relax(startKey, withWeight, longest)
+	keys := make([]string, 0, len(states))
+	for k := range states {
+		if k != startKey {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		res = append(res, d.buildPath(k, states))
+	}
+	return res
+}
+
+// relax returns the best paths from the source to the vertices reachable from it,
+// by relaxing edges in topological order in O(V+E).
+func (d *Graph[V, W]) relax(source string, withWeight, longest bool) map[string]*pathState[W] {
+	order := d.reachOrder(source, false)
+	states := make(map[string]*pathState[W], len(order))
+	if len(order) == 0 {
+		return states
+	}
+
+	states[source] = &pathState[W]{}
+	for _, k := range order {
+		s := states[k]
+		b := d.blocks[k]
+		for kk, w := range b.next {
+			cand := &pathState[W]{prev: k, weight: s.weight + w, hops: s.hops + 1}
+			cur, ok := states[kk]
+			if !ok {
+				states[kk] = cand
+				continue
+			}
+			if c := compareState(cand, cur, withWeight, longest); c < 0 || (c == 0 && cand.prev < cur.prev) {
+				states[kk] = cand
+			}
+		}
+	}
+	return states
+}
+
+// compareState returns a negative number if the path a is better than b, zero if they tie, otherwise a positive number.
+func compareState[W Weight](a, b *pathState[W], withWeight, longest bool) int {
+	c := 0
+	switch {
+	case withWeight && a.weight < b.weight, !withWeight && a.hops < b.hops:
+		c = -1
+	case withWeight && a.weight > b.weight, !withWeight && a.hops > b.hops:
+		c = 1
+	}
+	if longest {
+		c = -c
+	}
+	return c
+}
+
+// tiedPaths returns all the best paths from start to end in lexicographical order of the vertices UIDs.
+func (d *Graph[V, W]) tiedPaths(start, end V, withWeight, longest bool) []*Path[V, W] {
+	res := make([]*Path[V, W], 0)
+	if isNilVertice(start) || isNilVertice(end) {
+		return res
+	}
+	startKey, endKey := verticeUID(start), verticeUID(end)
+	states := d.relax(startKey, withWeight, longest)
+	if _, ok := states[endKey]; !ok || startKey == endKey {
+		return res
+	}
+
+	// tight reports whether the edge from k to kk is on a best path to kk.
+	tight := func(k, kk string) bool {
+		s, ok := states[k]
+		if !ok {
+			return false
+		}
+		cand := &pathState[W]{weight: s.weight + d.blocks[k].next[kk], hops: s.hops + 1}
+		return compareState(cand, states[kk], withWeight, longest) == 0
+	}
+
+	// the vertices on the best paths to end
+	onPath := map[string]bool{endKey: true}
+	queue := []string{endKey}
+	for len(queue) > 0 {
+		k := queue[0]
+		queue = queue[1:]
+		for kk := range d.blocks[k].prev {
+			if !onPath[kk] && tight(kk, k) {
+				onPath[kk] = true
+				queue = append(queue, kk)
+			}
+		}
+	}
+
+	keys := []string{startKey}
+	var iterator func(k string)
+	iterator = func(k string) {
+		if k == endKey {
+			res = append(res, d.keysToPath(keys))
+			return
+		}
+		for _, kk := range sortedKeys(d.blocks[k].next) {
+			if onPath[kk] && tight(k, kk) {
+				keys = append(keys, kk)
+				iterator(kk)
+				keys = keys[:len(keys)-1]
+			}
+		}
+	}
+	iterator(startKey)
+	return res
+}
+
+// reachOrder returns the keys of the vertices reachable from the source (inclusive) in topological order,
+// or the vertices can reach the source if reverse is true, returns nil if the source not exists.
+func (d *Graph[V, W]) reachOrder(source string, reverse bool) []string {
+	if _, ok := d.blocks[source]; !ok {
+		return nil
+	}
+	edges := func(k string) []string {
+		if reverse {
+			return sortedKeys(d.blocks[k].prev)
+		}
+		return sortedKeys(d.blocks[k].next)
+	}
+
+	type frame struct {
+		key  string
+		next []string
+	}
+	post := make([]string, 0)
+	visited := map[string]bool{source: true}
+	stack := []*frame{{key: source, next: edges(source)}}
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		if len(f.next) == 0 {
+			post = append(post, f.key)
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		k := f.next[0]
+		f.next = f.next[1:]
+		if !visited[k] {
+			visited[k] = true
+			stack = append(stack, &frame{key: k, next: edges(k)})
+		}
+	}
+	// the post order of reversed edges is a topological order
+	if reverse {
+		return post
+	}
+	for i, j := 0, len(post)-1; i < j; i, j = i+1, j-1 {
+		post[i], post[j] = post[j], post[i]
+	}
+	return post
+}
+
+func (d *Graph[V, W]) buildPath(target string, states map[string]*pathState[W]) *Path[V, W] {
+	keys := make([]string, states[target].hops+1)
+	for i, k := len(keys)-1, target; i >= 0; i-- {
+		keys[i] = k
+		k = states[k].prev
+	}
+	return d.keysToPath(keys)
+}
+
+func (d *Graph[V, W]) keysToPath(keys []string) *Path[V, W] {
+	p := &Path[V, W]{Vertices: make(List[V], len(keys)), Weights: make([]W, len(keys)-1)}
+	for i, k := range keys {
+		p.Vertices[i] = d.blocks[k].vertice
+		if i > 0 {
+			p.Weights[i-1] = d.blocks[keys[i-1]].next[k]
+			p.Total += p.Weights[i-1]
+		}
+	}
+	return p
+}
+
+func sortedKeys[W Weight](m map[string]W) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,278 @@
+package daggo_test
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	daggo "github.com/open-trust/dag-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPath(t *testing.T) {
+	t.Run("DAG.ShortestPath & LongestPath", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		assert.Nil(d.AddEdge(V("a"), V("b"), 1))
+		assert.Nil(d.AddEdge(V("b"), V("c"), 1))
+		assert.Nil(d.AddEdge(V("c"), V("e"), 1))
+		assert.Nil(d.AddEdge(V("a"), V("d"), 5))
+		assert.Nil(d.AddEdge(V("d"), V("e"), 5))
+		assert.Nil(d.AddVertice(V("x")))
+
+		p := d.ShortestPath(V("a"), V("e"), true)
+		assert.Equal(daggo.Vertices{V("a"), V("b"), V("c"), V("e")}, p.Vertices)
+		assert.Equal([]int{1, 1, 1}, p.Weights)
+		assert.Equal(3, p.Total)
+		assert.Equal(V("e"), p.End())
+
+		p = d.ShortestPath(V("a"), V("e"), false)
+		assert.Equal(daggo.Vertices{V("a"), V("d"), V("e")}, p.Vertices)
+		assert.Equal(10, p.Total)
+
+		p = d.LongestPath(V("a"), V("e"), true)
+		assert.Equal(daggo.Vertices{V("a"), V("d"), V("e")}, p.Vertices)
+		assert.Equal(10, p.Total)
+
+		p = d.LongestPath(V("a"), V("e"), false)
+		assert.Equal(daggo.Vertices{V("a"), V("b"), V("c"), V("e")}, p.Vertices)
+		assert.Equal(3, p.Total)
+
+		assert.Nil(d.ShortestPath(V("a"), V("a"), true))
+		assert.Nil(d.ShortestPath(V("e"), V("a"), true))
+		assert.Nil(d.ShortestPath(V("a"), V("x"), true))
+		assert.Nil(d.LongestPath(V("a"), V("z"), true))
+		assert.Nil(d.LongestPath(nil, V("e"), true))
+		assert.Equal(daggo.Vertices{}, d.Shortest(V("a"), V("x"), true))
+	})
+
+	t.Run("DAG.ShortestPaths & LongestPaths", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		assert.Nil(d.AddEdge(V("a"), V("b"), 1))
+		assert.Nil(d.AddEdge(V("b"), V("c"), 1))
+		assert.Nil(d.AddEdge(V("a"), V("c"), 3))
+		assert.Nil(d.AddEdge(V("x"), V("a"), 1))
+
+		ps := d.ShortestPaths(V("a"), true)
+		assert.Equal(2, len(ps))
+		assert.Equal(daggo.Vertices{V("a"), V("b")}, ps[0].Vertices)
+		assert.Equal(1, ps[0].Total)
+		assert.Equal(daggo.Vertices{V("a"), V("b"), V("c")}, ps[1].Vertices)
+		assert.Equal(2, ps[1].Total)
+
+		ps = d.LongestPaths(V("a"), true)
+		assert.Equal(2, len(ps))
+		assert.Equal(daggo.Vertices{V("a"), V("c")}, ps[1].Vertices)
+		assert.Equal(3, ps[1].Total)
+
+		assert.Equal(0, len(d.ShortestPaths(V("c"), true)))
+		assert.Equal(0, len(d.LongestPaths(V("z"), false)))
+	})
+
+	t.Run("DAG.AllShortestPaths & AllLongestPaths", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		assert.Nil(d.AddEdge(V("a"), V("c"), 1))
+		assert.Nil(d.AddEdge(V("a"), V("b"), 1))
+		assert.Nil(d.AddEdge(V("c"), V("d"), 2))
+		assert.Nil(d.AddEdge(V("b"), V("d"), 2))
+		assert.Nil(d.AddEdge(V("a"), V("d"), 3))
+		assert.Nil(d.AddEdge(V("d"), V("e"), 1))
+		assert.Nil(d.AddEdge(V("a"), V("x"), 9))
+
+		for i := 0; i < 10; i++ {
+			assert.Equal(daggo.Vertices{V("a"), V("b"), V("d"), V("e")}, d.LongestPath(V("a"), V("e"), false).Vertices)
+		}
+
+		ps := d.AllShortestPaths(V("a"), V("e"), true)
+		assert.Equal(3, len(ps))
+		assert.Equal(daggo.Vertices{V("a"), V("b"), V("d"), V("e")}, ps[0].Vertices)
+		assert.Equal([]int{1, 2, 1}, ps[0].Weights)
+		assert.Equal(daggo.Vertices{V("a"), V("c"), V("d"), V("e")}, ps[1].Vertices)
+		assert.Equal(daggo.Vertices{V("a"), V("d"), V("e")}, ps[2].Vertices)
+		assert.Equal([]int{3, 1}, ps[2].Weights)
+		for _, p := range ps {
+			assert.Equal(4, p.Total)
+		}
+		assert.Equal(daggo.Vertices{V("a"), V("d"), V("e")}, d.ShortestPath(V("a"), V("e"), true).Vertices)
+
+		ps = d.AllShortestPaths(V("a"), V("e"), false)
+		assert.Equal(1, len(ps))
+		assert.Equal(daggo.Vertices{V("a"), V("d"), V("e")}, ps[0].Vertices)
+
+		ps = d.AllLongestPaths(V("a"), V("e"), false)
+		assert.Equal(2, len(ps))
+		assert.Equal(daggo.Vertices{V("a"), V("b"), V("d"), V("e")}, ps[0].Vertices)
+		assert.Equal(daggo.Vertices{V("a"), V("c"), V("d"), V("e")}, ps[1].Vertices)
+
+		assert.Equal(0, len(d.AllShortestPaths(V("a"), V("a"), true)))
+		assert.Equal(0, len(d.AllLongestPaths(V("x"), V("e"), true)))
+	})
+
+	t.Run("DAG.KShortestPaths", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		assert.Nil(d.AddEdge(V("a"), V("b"), 1))
+		assert.Nil(d.AddEdge(V("a"), V("c"), 1))
+		assert.Nil(d.AddEdge(V("b"), V("d"), 2))
+		assert.Nil(d.AddEdge(V("c"), V("d"), 2))
+		assert.Nil(d.AddEdge(V("a"), V("d"), 3))
+		assert.Nil(d.AddEdge(V("d"), V("e"), 1))
+		assert.Nil(d.AddEdge(V("a"), V("e"), 5))
+		assert.Nil(d.AddEdge(V("b"), V("e"), 6))
+		assert.Nil(d.AddEdge(V("e"), V("x"), 1))
+
+		ps := d.KShortestPaths(V("a"), V("e"), 4, true)
+		assert.Equal(4, len(ps))
+		assert.Equal(daggo.Vertices{V("a"), V("b"), V("d"), V("e")}, ps[0].Vertices)
+		assert.Equal(daggo.Vertices{V("a"), V("c"), V("d"), V("e")}, ps[1].Vertices)
+		assert.Equal(daggo.Vertices{V("a"), V("d"), V("e")}, ps[2].Vertices)
+		assert.Equal(daggo.Vertices{V("a"), V("e")}, ps[3].Vertices)
+		assert.Equal([]int{4, 4, 4, 5}, []int{ps[0].Total, ps[1].Total, ps[2].Total, ps[3].Total})
+
+		ps = d.KShortestPaths(V("a"), V("e"), 10, true)
+		assert.Equal(5, len(ps))
+		assert.Equal(daggo.Vertices{V("a"), V("b"), V("e")}, ps[4].Vertices)
+		assert.Equal([]int{1, 6}, ps[4].Weights)
+
+		ps = d.KShortestPaths(V("a"), V("e"), 3, false)
+		assert.Equal(3, len(ps))
+		assert.Equal(daggo.Vertices{V("a"), V("e")}, ps[0].Vertices)
+		assert.Equal(daggo.Vertices{V("a"), V("b"), V("e")}, ps[1].Vertices)
+		assert.Equal(daggo.Vertices{V("a"), V("d"), V("e")}, ps[2].Vertices)
+
+		assert.Equal(0, len(d.KShortestPaths(V("a"), V("e"), 0, true)))
+		assert.Equal(0, len(d.KShortestPaths(V("a"), V("a"), 1, true)))
+		assert.Equal(0, len(d.KShortestPaths(V("x"), V("a"), 1, true)))
+		assert.Equal(0, len(d.KShortestPaths(V("a"), V("z"), 1, true)))
+	})
+
+	t.Run("Graph.Paths", func(t *testing.T) {
+		assert := assert.New(t)
+
+		user, res := T{"user", "x"}, T{"resource", "y"}
+		d := daggo.NewGraph[T, int]()
+		assert.Nil(d.AddEdge(user, T{"group", "g1"}, 1))
+		assert.Nil(d.AddEdge(user, T{"group", "g2"}, 1))
+		assert.Nil(d.AddEdge(T{"group", "g1"}, T{"role", "r"}, 1))
+		assert.Nil(d.AddEdge(T{"group", "g2"}, T{"role", "r"}, 1))
+		assert.Nil(d.AddEdge(T{"role", "r"}, res, 1))
+		assert.Nil(d.AddEdge(T{"group", "g2"}, res, 1))
+		assert.Nil(d.AddEdge(user, res, 1))
+		assert.Nil(d.AddEdge(user, T{"group", "g3"}, 1))
+
+		ids := func(opts *daggo.PathOptions) [][]string {
+			paths := make([][]string, 0)
+			d.Paths(user, res, opts, func(p *daggo.Path[T, int]) bool {
+				assert.Equal(len(p.Vertices)-1, p.Total)
+				paths = append(paths, p.Vertices.IDs())
+				return true
+			})
+			return paths
+		}
+		assert.Equal([][]string{
+			{"x", "g1", "r", "y"},
+			{"x", "g2", "y"},
+			{"x", "g2", "r", "y"},
+			{"x", "y"},
+		}, ids(nil))
+		assert.Equal([][]string{{"x", "g2", "y"}, {"x", "y"}}, ids(&daggo.PathOptions{MaxDepth: 2}))
+		assert.Equal([][]string{{"x", "g1", "r", "y"}, {"x", "g2", "y"}}, ids(&daggo.PathOptions{MaxCount: 2}))
+		assert.Equal([][]string{{"x", "g2", "y"}, {"x", "y"}}, ids(&daggo.PathOptions{Types: []string{"group"}}))
+		assert.Equal([][]string{{"x", "y"}}, ids(&daggo.PathOptions{Types: []string{"none"}}))
+
+		n := 0
+		d.Paths(user, res, nil, func(p *daggo.Path[T, int]) bool {
+			n++
+			return false
+		})
+		assert.Equal(1, n)
+
+		d.Paths(res, user, nil, func(p *daggo.Path[T, int]) bool {
+			n++
+			return true
+		})
+		d.Paths(user, user, nil, func(p *daggo.Path[T, int]) bool {
+			n++
+			return true
+		})
+		d.Paths(user, T{"group", "none"}, nil, func(p *daggo.Path[T, int]) bool {
+			n++
+			return true
+		})
+		assert.Equal(1, n)
+	})
+
+	t.Run("DAG.CountPaths & CountPathsTo", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		assert.Nil(d.AddEdge(V("a"), V("b"), 1))
+		assert.Nil(d.AddEdge(V("a"), V("c"), 1))
+		assert.Nil(d.AddEdge(V("b"), V("d"), 1))
+		assert.Nil(d.AddEdge(V("c"), V("d"), 1))
+		assert.Nil(d.AddEdge(V("a"), V("d"), 1))
+		assert.Nil(d.AddEdge(V("d"), V("e"), 1))
+		assert.Nil(d.AddEdge(V("x"), V("d"), 1))
+		assert.Nil(d.AddVertice(V("y")))
+
+		assert.Equal(int64(3), d.CountPaths(V("a"), V("d")).Int64())
+		assert.Equal(int64(3), d.CountPaths(V("a"), V("e")).Int64())
+		assert.Equal(int64(1), d.CountPaths(V("x"), V("e")).Int64())
+		assert.Equal(int64(0), d.CountPaths(V("e"), V("a")).Int64())
+		assert.Equal(int64(0), d.CountPaths(V("a"), V("a")).Int64())
+		assert.Equal(int64(0), d.CountPaths(V("a"), V("z")).Int64())
+		assert.Equal(int64(0), d.CountPaths(nil, V("a")).Int64())
+
+		assert.Equal(int64(4), d.CountPathsTo(V("e")).Int64())
+		assert.Equal(int64(1), d.CountPathsTo(V("b")).Int64())
+		assert.Equal(int64(1), d.CountPathsTo(V("a")).Int64())
+		assert.Equal(int64(1), d.CountPathsTo(V("y")).Int64())
+		assert.Equal(int64(0), d.CountPathsTo(V("z")).Int64())
+	})
+
+	t.Run("should work with deep diamonds", func(t *testing.T) {
+		assert := assert.New(t)
+
+		// 2^200 paths from v000 to v200
+		d := daggo.NewGraph[V, float64]()
+		for i := 0; i < 200; i++ {
+			from, to := V(fmt.Sprintf("v%03d", i)), V(fmt.Sprintf("v%03d", i+1))
+			assert.Nil(d.AddEdge(from, V(fmt.Sprintf("l%03d", i)), 1))
+			assert.Nil(d.AddEdge(V(fmt.Sprintf("l%03d", i)), to, 1))
+			assert.Nil(d.AddEdge(from, V(fmt.Sprintf("r%03d", i)), 2))
+			assert.Nil(d.AddEdge(V(fmt.Sprintf("r%03d", i)), to, 2))
+		}
+
+		p := d.ShortestPath(V("v000"), V("v200"), true)
+		assert.Equal(401, len(p.Vertices))
+		assert.Equal(400.0, p.Total)
+		assert.Equal(V("l000"), p.Vertices[1])
+
+		p = d.LongestPath(V("v000"), V("v200"), true)
+		assert.Equal(800.0, p.Total)
+		assert.Equal(V("r199"), p.Vertices[399])
+		assert.Equal(600, len(d.LongestPaths(V("v000"), false)))
+
+		ps := d.KShortestPaths(V("v000"), V("v200"), 3, true)
+		assert.Equal(3, len(ps))
+		assert.Equal([]float64{400, 402, 402}, []float64{ps[0].Total, ps[1].Total, ps[2].Total})
+		assert.Equal(V("r199"), ps[1].Vertices[399])
+		assert.Equal(V("r198"), ps[2].Vertices[397])
+
+		n := 0
+		d.Paths(V("v000"), V("v200"), &daggo.PathOptions{MaxCount: 1000}, func(p *daggo.Path[V, float64]) bool {
+			n++
+			return true
+		})
+		assert.Equal(1000, n)
+
+		assert.Equal(new(big.Int).Lsh(big.NewInt(1), 200), d.CountPaths(V("v000"), V("v200")))
+		assert.Equal(new(big.Int).Lsh(big.NewInt(1), 200), d.CountPathsTo(V("v200")))
+	})
+}
@@ -0,0 +1,229 @@
+package daggo
+
+// ReachIndex is a reachability index of a DAG, it answers reachability queries in constant time.
+type ReachIndex[V Vertice, W Weight] struct {
+	d *Graph[V, W]
+}
+
+// ReachIndex returns the reachability index of the DAG, builds and attaches it to the DAG if not yet.
+// The attached index is maintained by AddVertice, AddEdge, RemoveEdge, RemoveVertice and ContractVertice,
+// rebuilt after bulk mutations such as Merge and Tx.Commit,
+// and used by IsReachable, LowestCommonAncestors and CommonDescendants.
+// The index takes O(V^2) bits memory, it is not copied by Clone.
+func (d *Graph[V, W]) ReachIndex() *ReachIndex[V, W] {
+	d.lock()
+	defer d.unlock()
+
+	if d.index == nil {
+		d.buildReachIndex()
+	}
+	return &ReachIndex[V, W]{d: d}
+}
+
+// DropReachIndex detaches the reachability index from the DAG.
+func (d *Graph[V, W]) DropReachIndex() {
+	d.lock()
+	defer d.unlock()
+
+	d.index = nil
+}
+
+// Reachable returns whether there is a path from the vertice a to b.
+// It is the same as DAG.IsReachable, and falls back to search the DAG if the index is dropped.
+func (x *ReachIndex[V, W]) Reachable(a, b V) bool {
+	return x.d.IsReachable(a, b)
+}
+
+// reachIndex is the transitive closure of a DAG in bitsets.
+// A removed vertice leaves an unused id until the index is rebuilt.
+type reachIndex struct {
+	ids  map[string]int
+	desc []bitset // desc[i] is the descendants of the vertice with id i
+}
+
+// buildReachIndex builds and attaches a new index.
+func (d *Graph[V, W]) buildReachIndex() {
+	d.index = &reachIndex{ids: make(map[string]int, len(d.blocks))}
+	keys := d.topoKeys(nil)
+	for _, k := range keys {
+		d.index.id(k)
+	}
+	d.updateReachIndex(keys)
+}
+
+// reindex rebuilds the attached index after bulk mutations.
+func (d *Graph[V, W]) reindex() {
+	if d.index != nil {
+		d.buildReachIndex()
+	}
+}
+
+func (x *reachIndex) id(k string) int {
+	i, ok := x.ids[k]
+	if !ok {
+		i = len(x.desc)
+		x.ids[k] = i
+		x.desc = append(x.desc, nil)
+	}
+	return i
+}
+
+func (x *reachIndex) reachable(a, b string) bool {
+	i, ok := x.ids[a]
+	if !ok {
+		return false
+	}
+	j, ok := x.ids[b]
+	return ok && x.desc[i].has(j)
+}
+
+// common returns the common ancestors (inclusive) of the keys, or the common descendants if ancestors is false.
+func (x *reachIndex) common(keys []string, ancestors bool) map[string]bool {
+	for _, k := range keys {
+		// a vertice not in the index has no connecting, it is only the ancestor and descendant of itself
+		if _, ok := x.ids[k]; !ok {
+			for _, kk := range keys {
+				if kk != k {
+					return map[string]bool{}
+				}
+			}
+			return map[string]bool{k: true}
+		}
+	}
+
+	set := bitset{}
+	if ancestors {
+		target := bitset{}
+		for _, k := range keys {
+			target.set(x.ids[k])
+		}
+		for _, i := range x.ids {
+			if x.desc[i].covers(target, i) {
+				set.set(i)
+			}
+		}
+	} else {
+		for n, k := range keys {
+			i := x.ids[k]
+			row := append(bitset{}, x.desc[i]...)
+			row.set(i)
+			if n == 0 {
+				set = row
+			} else {
+				set.and(row)
+			}
+		}
+	}
+
+	common := make(map[string]bool)
+	for k, i := range x.ids {
+		if set.has(i) {
+			common[k] = true
+		}
+	}
+	return common
+}
+
+// lowest returns the keys that no other key in them is reachable from.
+func (x *reachIndex) lowest(keys []string) []string {
+	set := bitset{}
+	for _, k := range keys {
+		if i, ok := x.ids[k]; ok {
+			set.set(i)
+		}
+	}
+	res := make([]string, 0)
+	for _, k := range keys {
+		// a vertice not in the index reaches no other vertice
+		if i, ok := x.ids[k]; !ok || !x.desc[i].intersects(set) {
+			res = append(res, k)
+		}
+	}
+	return res
+}
+
+// addEdge adds the descendants of end to start and its ancestors.
+func (x *reachIndex) addEdge(start, end string) {
+	i, j := x.id(start), x.id(end)
+	row := append(bitset{}, x.desc[j]...)
+	row.set(j)
+	for k := range x.desc {
+		if k == i || x.desc[k].has(i) {
+			x.desc[k].or(row)
+		}
+	}
+}
+
+// updateReachIndex recomputes the descendants of the vertices in the attached index,
+// keys should be in topological order and include all the ancestors of a vertice in it.
+func (d *Graph[V, W]) updateReachIndex(keys []string) {
+	x := d.index
+	for n := len(keys) - 1; n >= 0; n-- {
+		row := bitset{}
+		for kk := range d.blocks[keys[n]].next {
+			j := x.id(kk)
+			row.set(j)
+			row.or(x.desc[j])
+		}
+		x.desc[x.id(keys[n])] = row
+	}
+}
+
+type bitset []uint64
+
+func (s bitset) has(i int) bool {
+	return i/64 < len(s) && s[i/64]&(1<<(uint(i)%64)) != 0
+}
+
+func (s *bitset) set(i int) {
+	s.grow(i/64 + 1)
+	(*s)[i/64] |= 1 << (uint(i) % 64)
+}
+
+func (s *bitset) or(x bitset) {
+	s.grow(len(x))
+	for i, w := range x {
+		(*s)[i] |= w
+	}
+}
+
+func (s *bitset) and(x bitset) {
+	for i := range *s {
+		if i < len(x) {
+			(*s)[i] &= x[i]
+		} else {
+			(*s)[i] = 0
+		}
+	}
+}
+
+func (s bitset) intersects(x bitset) bool {
+	for i := 0; i < len(s) && i < len(x); i++ {
+		if s[i]&x[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// covers reports whether s with the bit i includes all the bits of x.
+func (s bitset) covers(x bitset, i int) bool {
+	for n, w := range x {
+		if n == i/64 {
+			w &^= 1 << (uint(i) % 64)
+		}
+		if n < len(s) {
+			w &^= s[n]
+		}
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *bitset) grow(n int) {
+	if n > len(*s) {
+		*s = append(*s, make(bitset, n-len(*s))...)
+	}
+}
@@ -0,0 +1,106 @@
+package daggo_test
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	daggo "github.com/open-trust/dag-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReachIndex(t *testing.T) {
+	t.Run("DAG.ReachIndex", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		assert.Nil(d.AddEdge(V("a"), V("b"), 1))
+		assert.Nil(d.AddEdge(V("b"), V("c"), 1))
+		assert.Nil(d.AddVertice(V("x")))
+
+		x := d.ReachIndex()
+		assert.True(x.Reachable(V("a"), V("c")))
+		assert.False(x.Reachable(V("c"), V("a")))
+		assert.False(x.Reachable(V("a"), V("a")))
+		assert.False(x.Reachable(V("a"), V("x")))
+		assert.False(x.Reachable(V("a"), V("z")))
+		assert.False(x.Reachable(nil, V("a")))
+
+		assert.Nil(d.AddEdge(V("c"), V("x"), 1))
+		assert.True(x.Reachable(V("a"), V("x")))
+		assert.True(d.IsReachable(V("b"), V("x")))
+		err := d.AddEdge(V("x"), V("a"), 1)
+		assert.True(errors.Is(err, &daggo.ErrCycle{}))
+		assert.Equal("cyclic graph will come into being: test:x -> test:a -> test:b -> test:c -> test:x", err.Error())
+
+		d.RemoveEdge(V("b"), V("c"))
+		assert.False(x.Reachable(V("a"), V("x")))
+		assert.True(x.Reachable(V("c"), V("x")))
+
+		d.RemoveVertice(V("c"))
+		assert.Nil(d.AddEdge(V("c"), V("a"), 1))
+		assert.True(x.Reachable(V("c"), V("b")))
+		assert.False(x.Reachable(V("c"), V("x")))
+
+		d.DropReachIndex()
+		assert.True(x.Reachable(V("c"), V("b")))
+		assert.True(d.ReachIndex().Reachable(V("c"), V("b")))
+	})
+
+	t.Run("should be maintained by mutations", func(t *testing.T) {
+		assert := assert.New(t)
+
+		r := rand.New(rand.NewSource(1))
+		v := func(i int) V { return V(fmt.Sprintf("v%02d", i)) }
+		check := func(d *daggo.DAG, step string) {
+			// a clone has no index, it searches the DAG
+			c := d.Clone()
+			for i := 0; i < 30; i++ {
+				for j := 0; j < 30; j++ {
+					assert.Equal(c.IsReachable(v(i), v(j)), d.IsReachable(v(i), v(j)), "%s: %d -> %d", step, i, j)
+					assert.Equal(c.LowestCommonAncestors(v(i), v(j)), d.LowestCommonAncestors(v(i), v(j)), "%s: %d, %d", step, i, j)
+					assert.Equal(c.CommonDescendants(v(i), v(j)), d.CommonDescendants(v(i), v(j)), "%s: %d, %d", step, i, j)
+				}
+			}
+		}
+
+		d := daggo.NewSync()
+		d.ReachIndex()
+		for n := 0; n < 300; n++ {
+			i, j := r.Intn(30), r.Intn(30)
+			if i > j {
+				i, j = j, i
+			}
+			switch op := r.Intn(10); {
+			case op < 5 && i != j:
+				assert.Nil(d.AddEdge(v(i), v(j), 1))
+				assert.NotNil(d.AddEdge(v(j), v(i), 1))
+			case op < 7:
+				d.RemoveEdge(v(i), v(j))
+			case op < 8:
+				d.RemoveVertice(v(i))
+			case op < 9:
+				d.ContractVertice(v(i), nil)
+			default:
+				_ = d.AddVertice(v(i))
+			}
+			if n%20 == 0 {
+				check(d, fmt.Sprintf("step %d", n))
+			}
+		}
+		check(d, "mutations")
+
+		a := daggo.New()
+		assert.Nil(a.AddEdge(v(29), v(30), 1))
+		assert.Nil(a.AddEdge(v(0), v(29), 1))
+		assert.Nil(d.Merge(a))
+		check(d, "merge")
+
+		tx := d.Begin()
+		tx.RemoveVertice(v(29))
+		assert.Nil(tx.AddEdge(v(1), v(28), 1))
+		assert.Nil(tx.Commit())
+		check(d, "commit")
+	})
+}
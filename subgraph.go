@@ -0,0 +1,93 @@
+package daggo
+
+import (
+	"sort"
+)
+
+// Subgraph returns a new sub DAG induced by the vertices that fn returns true.
+// The fn is called with the read lock held, it should not call any method of the DAG,
+// otherwise it may deadlock a DAG created by NewSync.
+func (d *Graph[V, W]) Subgraph(fn func(v V) bool) *Graph[V, W] {
+	d.rlock()
+	defer d.runlock()
+
+	return d.induced(d.filterSet(fn))
+}
+
+// Project returns a new DAG with the vertices that fn returns true, and the reachability relation between them is kept:
+// two vertices are connected if there is a path between them through the filtered out vertices only.
+// The weight of such a connecting is the minimum of the weights of the paths combined by the combine function,
+// and summed if combine is nil. The existing direct connecting is kept.
+// The fn and combine are called with the read lock held, they should not call any method of the DAG,
+// otherwise it may deadlock a DAG created by NewSync.
+func (d *Graph[V, W]) Project(fn func(v V) bool, combine func(prev, next W) W) *Graph[V, W] {
+	d.rlock()
+	defer d.runlock()
+
+	if combine == nil {
+		combine = func(prev, next W) W { return prev + next }
+	}
+	set := d.filterSet(fn)
+	nd := d.induced(set)
+	pos := make(map[string]int, len(d.blocks))
+	for i, k := range d.topoKeys(nil) {
+		pos[k] = i
+	}
+
+	for k := range set {
+		// the filtered out vertices reachable from k through filtered out vertices only, in topological order
+		found := make(map[string]bool)
+		queue := []string{k}
+		for i := 0; i < len(queue); i++ {
+			for kk := range d.blocks[queue[i]].next {
+				if !found[kk] && !set[kk] {
+					found[kk] = true
+					queue = append(queue, kk)
+				}
+			}
+		}
+		queue = queue[1:]
+		sort.Slice(queue, func(i, j int) bool { return pos[queue[i]] < pos[queue[j]] })
+
+		// dist is the min weight from k to the filtered out vertices
+		dist := make(map[string]W, len(queue))
+		for kk, w := range d.blocks[k].next {
+			if !set[kk] {
+				dist[kk] = w
+			}
+		}
+		nb := nd.blocks[k]
+		for _, x := range queue {
+			for kk, w := range d.blocks[x].next {
+				w = combine(dist[x], w)
+				if !set[kk] {
+					if cur, ok := dist[kk]; !ok || w < cur {
+						dist[kk] = w
+					}
+					continue
+				}
+				if _, ok := d.blocks[k].next[kk]; ok {
+					continue
+				}
+				if cur, ok := nb.next[kk]; !ok || w < cur {
+					nb.next[kk] = w
+					nd.blocks[kk].prev[k] = w
+				}
+			}
+		}
+	}
+	return nd
+}
+
+// filterSet returns the keys of the vertices that fn returns true.
+func (d *Graph[V, W]) filterSet(fn func(v V) bool) map[string]bool {
+	keys := make([]string, 0, len(d.blocks))
+	for k := range d.blocks {
+		keys = append(keys, k)
+	}
+	set := make(map[string]bool)
+	for _, v := range d.keysToList(keys).Filter(fn) {
+		set[verticeUID(v)] = true
+	}
+	return set
+}
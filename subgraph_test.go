@@ -0,0 +1,77 @@
+package daggo_test
+
+import (
+	"testing"
+
+	daggo "github.com/open-trust/dag-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubgraph(t *testing.T) {
+	u, g, r, r2 := T{"user", "u"}, T{"group", "g"}, T{"role", "r"}, T{"role", "r2"}
+	keep := func(v T) bool { return v.Kind == "user" || v.Kind == "group" || v.Kind == "role" }
+
+	t.Run("Graph.Subgraph", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.NewGraph[T, int]()
+		assert.Nil(d.AddEdge(u, T{"team", "t"}, 1))
+		assert.Nil(d.AddEdge(T{"team", "t"}, g, 2))
+		assert.Nil(d.AddEdge(u, g, 10))
+		assert.Nil(d.AddEdge(g, T{"perm", "p"}, 1))
+		assert.Nil(d.AddEdge(T{"perm", "p"}, r, 1))
+		assert.Nil(d.AddEdge(g, T{"perm", "q"}, 5))
+		assert.Nil(d.AddEdge(T{"perm", "q"}, r, 1))
+		assert.Nil(d.AddEdge(r, T{"resource", "x"}, 1))
+		assert.Nil(d.AddEdge(T{"team", "t"}, T{"perm", "s"}, 1))
+		assert.Nil(d.AddEdge(T{"perm", "s"}, r2, 1))
+
+		x := daggo.NewGraph[T, int]()
+		assert.Nil(x.AddEdge(u, g, 10))
+		assert.Nil(x.AddVertice(r))
+		assert.Nil(x.AddVertice(r2))
+		assert.True(x.Equal(d.Subgraph(keep)))
+
+		assert.Equal(0, d.Subgraph(func(v T) bool { return false }).Len())
+		assert.True(d.Equal(d.Subgraph(func(v T) bool { return true })))
+	})
+
+	t.Run("Graph.Project", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.NewGraph[T, int]()
+		assert.Nil(d.AddEdge(u, T{"team", "t"}, 1))
+		assert.Nil(d.AddEdge(T{"team", "t"}, g, 2))
+		assert.Nil(d.AddEdge(u, g, 10))
+		assert.Nil(d.AddEdge(g, T{"perm", "p"}, 1))
+		assert.Nil(d.AddEdge(T{"perm", "p"}, r, 1))
+		assert.Nil(d.AddEdge(g, T{"perm", "q"}, 5))
+		assert.Nil(d.AddEdge(T{"perm", "q"}, r, 1))
+		assert.Nil(d.AddEdge(r, T{"resource", "x"}, 1))
+		assert.Nil(d.AddEdge(T{"team", "t"}, T{"perm", "s"}, 1))
+		assert.Nil(d.AddEdge(T{"perm", "s"}, r2, 1))
+
+		x := daggo.NewGraph[T, int]()
+		assert.Nil(x.AddEdge(u, g, 10))
+		assert.Nil(x.AddEdge(g, r, 2))
+		assert.Nil(x.AddEdge(u, r2, 3))
+		assert.True(x.Equal(d.Project(keep, nil)))
+
+		p := d.Project(keep, func(prev, next int) int {
+			if prev > next {
+				return prev
+			}
+			return next
+		})
+		assert.Equal(daggo.List[T]{g, r2}, p.ToVertices(u).Sort())
+		assert.Equal(1, p.ShortestPath(g, r, true).Total)
+		assert.Equal(1, p.ShortestPath(u, r2, true).Total)
+
+		for _, a := range d.Vertices("").Filter(keep) {
+			for _, b := range d.Vertices("").Filter(keep) {
+				assert.Equal(d.IsReachable(a, b), p.IsReachable(a, b))
+			}
+		}
+		assert.True(d.Equal(d.Project(func(v T) bool { return true }, nil)))
+	})
+}
@@ -0,0 +1,308 @@
+package daggo
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// TopologicalSort returns all vertices in the DAG in topological order,
+// every vertice comes before the vertices it connected to.
+// Vertices without order between them are sorted by vertice UID ("type:id"), so the order is stable across runs.
+func (d *Graph[V, W]) TopologicalSort() List[V] {
+	d.rlock()
+	defer d.runlock()
+
+	return d.keysToList(d.topoKeys(nil))
+}
+
+// TopologicalSortFunc is like TopologicalSort, but vertices without order between them are sorted by the less function,
+// vertices that are equal in the less function are sorted by vertice UID.
+// The less is called with the read lock held, it should not call any method of the DAG,
+// otherwise it may deadlock a DAG created by NewSync.
+func (d *Graph[V, W]) TopologicalSortFunc(less func(a, b V) bool) List[V] {
+	d.rlock()
+	defer d.runlock()
+
+	return d.keysToList(d.topoKeys(func(a, b string) bool {
+		va, vb := d.blocks[a].vertice, d.blocks[b].vertice
+		if less(va, vb) {
+			return true
+		}
+		if less(vb, va) {
+			return false
+		}
+		return a < b
+	}))
+}
+
+// Layers returns vertices grouped into layers by the longest path from the starting vertices,
+// every vertice in layer N only connected from vertices in layers < N.
+// Vertices in a layer are sorted by vertice UID.
+func (d *Graph[V, W]) Layers() []List[V] {
+	d.rlock()
+	defer d.runlock()
+
+	res := make([]List[V], 0)
+	keys := d.topoKeys(nil)
+	depths := d.depths(keys)
+	for _, k := range keys {
+		n := depths[k]
+		for len(res) <= n {
+			res = append(res, make([]V, 0))
+		}
+		res[n] = append(res[n], d.blocks[k].vertice)
+	}
+	for _, l := range res {
+		l.Sort()
+	}
+	return res
+}
+
+// Depth returns the length of the longest path from a starting vertice to the vertice v,
+// the vertice v is in the layer Depth(v) of Layers. Returns -1 if v not found.
+func (d *Graph[V, W]) Depth(v V) int {
+	d.rlock()
+	defer d.runlock()
+
+	if isNilVertice(v) {
+		return -1
+	}
+	k := verticeUID(v)
+	if _, ok := d.blocks[k]; !ok {
+		return -1
+	}
+	return d.depths(d.topoKeys(nil))[k]
+}
+
+// Height returns the length of the longest path from the vertice v to an ending vertice. Returns -1 if v not found.
+func (d *Graph[V, W]) Height(v V) int {
+	d.rlock()
+	defer d.runlock()
+
+	if isNilVertice(v) {
+		return -1
+	}
+	k := verticeUID(v)
+	if _, ok := d.blocks[k]; !ok {
+		return -1
+	}
+
+	keys := d.topoKeys(nil)
+	heights := make(map[string]int, len(keys))
+	for i := len(keys) - 1; i >= 0; i-- {
+		h := 0
+		for kk := range d.blocks[keys[i]].next {
+			if heights[kk]+1 > h {
+				h = heights[kk] + 1
+			}
+		}
+		heights[keys[i]] = h
+	}
+	return heights[k]
+}
+
+// depths returns the longest path length from starting vertices to every block, keys should be in topological order.
+func (d *Graph[V, W]) depths(keys []string) map[string]int {
+	res := make(map[string]int, len(keys))
+	for _, k := range keys {
+		n := 0
+		for kk := range d.blocks[k].prev {
+			if res[kk]+1 > n {
+				n = res[kk] + 1
+			}
+		}
+		res[k] = n
+	}
+	return res
+}
+
+func (d *Graph[V, W]) keysToList(keys []string) List[V] {
+	res := make([]V, len(keys))
+	for i, k := range keys {
+		res[i] = d.blocks[k].vertice
+	}
+	return res
+}
+
+// topoKeys returns the keys of blocks in topological order by Kahn's algorithm,
+// the less function breaks ties and defaults to keys order.
+// The result is shorter than the blocks if the graph is cyclic.
+func (d *Graph[V, W]) topoKeys(less func(a, b string) bool) []string {
+	if less == nil {
+		less = func(a, b string) bool { return a < b }
+	}
+	degrees := make(map[string]int, len(d.blocks))
+	h := &keyHeap{less: less}
+	for k, b := range d.blocks {
+		degrees[k] = len(b.prev)
+		if len(b.prev) == 0 {
+			h.keys = append(h.keys, k)
+		}
+	}
+	heap.Init(h)
+
+	res := make([]string, 0, len(d.blocks))
+	for h.Len() > 0 {
+		k := heap.Pop(h).(string)
+		res = append(res, k)
+		for kk := range d.blocks[k].next {
+			degrees[kk]--
+			if degrees[kk] == 0 {
+				heap.Push(h, kk)
+			}
+		}
+	}
+	return res
+}
+
+// acyclicKeys returns the keys of blocks that Kahn's algorithm can sort, in linear time without ordering ties.
+// They are all the keys if the graph is acyclic.
+func (d *Graph[V, W]) acyclicKeys() map[string]bool {
+	degrees := make(map[string]int, len(d.blocks))
+	queue := make([]string, 0, len(d.blocks))
+	for k, b := range d.blocks {
+		degrees[k] = len(b.prev)
+		if len(b.prev) == 0 {
+			queue = append(queue, k)
+		}
+	}
+
+	res := make(map[string]bool, len(d.blocks))
+	for i := 0; i < len(queue); i++ {
+		k := queue[i]
+		res[k] = true
+		for kk := range d.blocks[k].next {
+			degrees[kk]--
+			if degrees[kk] == 0 {
+				queue = append(queue, kk)
+			}
+		}
+	}
+	return res
+}
+
+// cycleKeys returns the keys of a cycle in the graph, its first key is also its last key.
+// Returns nil if the graph is acyclic.
+func (d *Graph[V, W]) cycleKeys() []string {
+	sorted := d.acyclicKeys()
+	if len(sorted) == len(d.blocks) {
+		return nil
+	}
+
+	// every remaining block has a remaining prev block, walk back along them until a block repeats.
+	remaining := make([]string, 0, len(d.blocks)-len(sorted))
+	for k := range d.blocks {
+		if !sorted[k] {
+			remaining = append(remaining, k)
+		}
+	}
+	sort.Strings(remaining)
+
+	index := make(map[string]int)
+	path := make([]string, 0)
+	k := remaining[0]
+	for {
+		if i, ok := index[k]; ok {
+			path = append(path[i:], k)
+			break
+		}
+		index[k] = len(path)
+		path = append(path, k)
+		prev := ""
+		for pk := range d.blocks[k].prev {
+			if !sorted[pk] && (prev == "" || pk < prev) {
+				prev = pk
+			}
+		}
+		k = prev
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+type keyHeap struct {
+	keys []string
+	less func(a, b string) bool
+}
+
+func (h *keyHeap) Len() int           { return len(h.keys) }
+func (h *keyHeap) Less(i, j int) bool { return h.less(h.keys[i], h.keys[j]) }
+func (h *keyHeap) Swap(i, j int)      { h.keys[i], h.keys[j] = h.keys[j], h.keys[i] }
+func (h *keyHeap) Push(x interface{}) { h.keys = append(h.keys, x.(string)) }
+func (h *keyHeap) Pop() interface{} {
+	n := len(h.keys)
+	x := h.keys[n-1]
+	h.keys = h.keys[:n-1]
+	return x
+}
+
+// orderEdge maintains the topological order of the DAG for a new edge from start to end in the Pearce-Kelly way,
+// returns false if the edge forms a cyclic graph. Only the vertices between end and start in the order are visited,
+// so adding an edge that agrees with the order needs no traversal.
+func (d *Graph[V, W]) orderEdge(start, end string) bool {
+	if d.ord == nil {
+		keys := d.topoKeys(nil)
+		d.ord = make(map[string]int, len(keys))
+		for i, k := range keys {
+			d.ord[k] = i
+		}
+		d.minOrd, d.maxOrd = 0, len(keys)-1
+	}
+	// a new start vertice goes before all vertices, and a new end vertice goes after all vertices
+	if _, ok := d.ord[start]; !ok {
+		d.minOrd--
+		d.ord[start] = d.minOrd
+	}
+	if _, ok := d.ord[end]; !ok {
+		d.maxOrd++
+		d.ord[end] = d.maxOrd
+	}
+	lb, ub := d.ord[end], d.ord[start]
+	if lb > ub {
+		return true
+	}
+
+	// the vertices reachable from end and before start
+	visited := map[string]bool{end: true, start: true}
+	fwd := []string{end}
+	for i := 0; i < len(fwd); i++ {
+		for k := range d.blocks[fwd[i]].next {
+			if k == start {
+				return false
+			}
+			if !visited[k] && d.ord[k] < ub {
+				visited[k] = true
+				fwd = append(fwd, k)
+			}
+		}
+	}
+	// the vertices can reach start and after end
+	bwd := []string{start}
+	for i := 0; i < len(bwd); i++ {
+		for k := range d.blocks[bwd[i]].prev {
+			if !visited[k] && d.ord[k] > lb {
+				visited[k] = true
+				bwd = append(bwd, k)
+			}
+		}
+	}
+
+	// reassign the orders of the vertices, bwd before fwd
+	byOrd := func(keys []string) {
+		sort.Slice(keys, func(i, j int) bool { return d.ord[keys[i]] < d.ord[keys[j]] })
+	}
+	byOrd(fwd)
+	byOrd(bwd)
+	keys := append(bwd, fwd...)
+	ords := make([]int, len(keys))
+	for i, k := range keys {
+		ords[i] = d.ord[k]
+	}
+	sort.Ints(ords)
+	for i, k := range keys {
+		d.ord[k] = ords[i]
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package daggo_test
+
+import (
+	"testing"
+
+	daggo "github.com/open-trust/dag-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopologicalSort(t *testing.T) {
+	t.Run("DAG.TopologicalSort", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		assert.Equal(daggo.Vertices{}, d.TopologicalSort())
+
+		assert.Nil(d.AddEdge(V("a"), V("b"), 0))
+		assert.Nil(d.AddEdge(V("a"), V("c"), 0))
+		assert.Nil(d.AddEdge(V("a"), V("d"), 0))
+		assert.Nil(d.AddEdge(V("a"), V("e"), 0))
+		assert.Nil(d.AddEdge(V("b"), V("d"), 0))
+		assert.Nil(d.AddEdge(V("c"), V("d"), 0))
+		assert.Nil(d.AddEdge(V("c"), V("e"), 0))
+		assert.Nil(d.AddEdge(V("d"), V("e"), 0))
+		assert.Nil(d.AddEdge(V("x"), V("b"), 0))
+		assert.Nil(d.AddEdge(V("d"), V("y"), 0))
+
+		for i := 0; i < 10; i++ {
+			assert.Equal([]string{"a", "c", "x", "b", "d", "e", "y"}, d.TopologicalSort().IDs())
+		}
+		assert.Equal([]string{"x", "a", "c", "b", "d", "y", "e"}, d.TopologicalSortFunc(func(a, b daggo.Vertice) bool {
+			return a.ID() > b.ID()
+		}).IDs())
+		assert.Equal([]string{"a", "c", "x", "b", "d", "e", "y"}, d.TopologicalSortFunc(func(a, b daggo.Vertice) bool {
+			return false
+		}).IDs())
+	})
+
+	t.Run("Graph.TopologicalSortFunc", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.NewGraph[T, int]()
+		assert.Nil(d.AddEdge(T{"user", "a"}, T{"group", "b"}, 0))
+		assert.Nil(d.AddEdge(T{"user", "c"}, T{"group", "b"}, 0))
+		assert.Nil(d.AddEdge(T{"role", "d"}, T{"group", "b"}, 0))
+
+		priority := map[string]int{"role": 0, "user": 1, "group": 2}
+		assert.Equal(daggo.List[T]{{"role", "d"}, {"user", "a"}, {"user", "c"}, {"group", "b"}}, d.TopologicalSortFunc(func(a, b T) bool {
+			return priority[a.Kind] < priority[b.Kind]
+		}))
+	})
+}
+
+func TestLayers(t *testing.T) {
+	t.Run("DAG.Layers", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		assert.Equal([]daggo.Vertices{}, d.Layers())
+		assert.Equal(-1, d.Depth(V("a")))
+		assert.Equal(-1, d.Height(nil))
+
+		assert.Nil(d.AddEdge(V("a"), V("b"), 0))
+		assert.Nil(d.AddEdge(V("a"), V("c"), 0))
+		assert.Nil(d.AddEdge(V("a"), V("d"), 0))
+		assert.Nil(d.AddEdge(V("a"), V("e"), 0))
+		assert.Nil(d.AddEdge(V("b"), V("d"), 0))
+		assert.Nil(d.AddEdge(V("c"), V("d"), 0))
+		assert.Nil(d.AddEdge(V("c"), V("e"), 0))
+		assert.Nil(d.AddEdge(V("d"), V("e"), 0))
+		assert.Nil(d.AddEdge(V("x"), V("b"), 0))
+		assert.Nil(d.AddEdge(V("d"), V("y"), 0))
+
+		layers := d.Layers()
+		assert.Equal(4, len(layers))
+		assert.Equal([]string{"a", "x"}, layers[0].IDs())
+		assert.Equal([]string{"b", "c"}, layers[1].IDs())
+		assert.Equal([]string{"d"}, layers[2].IDs())
+		assert.Equal([]string{"e", "y"}, layers[3].IDs())
+
+		assert.Equal(0, d.Depth(V("x")))
+		assert.Equal(2, d.Depth(V("d")))
+		assert.Equal(3, d.Depth(V("e")))
+		assert.Equal(3, d.Height(V("a")))
+		assert.Equal(3, d.Height(V("x")))
+		assert.Equal(1, d.Height(V("d")))
+		assert.Equal(0, d.Height(V("y")))
+		assert.Equal(-1, d.Height(V("z")))
+	})
+}
@@ -0,0 +1,161 @@
+package daggo
+
+type txOpKind int
+
+const (
+	txAddVertice txOpKind = iota
+	txAddEdge
+	txRemoveEdge
+	txRemoveVertice
+)
+
+type txOp[V Vertice, W Weight] struct {
+	kind   txOpKind
+	start  V
+	end    V
+	weight W
+}
+
+// Tx is a batch of mutations on a DAG.
+// The mutations are staged and applied to the DAG atomically by Commit,
+// and the acyclicity is validated only once at Commit.
+// A Tx is not safe for concurrent use.
+type Tx[V Vertice, W Weight] struct {
+	d   *Graph[V, W]
+	ops []txOp[V, W]
+}
+
+// Begin starts a batch of mutations on the DAG.
+func (d *Graph[V, W]) Begin() *Tx[V, W] {
+	return &Tx[V, W]{d: d}
+}
+
+// Len returns the count of staged mutations.
+func (tx *Tx[V, W]) Len() int {
+	return len(tx.ops)
+}
+
+// AddVertice stages adding a vertice without any connecting,
+// the vertice should not be nil and not exist in the DAG when committing.
+func (tx *Tx[V, W]) AddVertice(v V) error {
+	if isNilVertice(v) || v.ID() == "" {
+		return &ErrInvalidVertice{Vertice: v}
+	}
+	tx.ops = append(tx.ops, txOp[V, W]{kind: txAddVertice, start: v})
+	return nil
+}
+
+// AddEdge stages adding a connecting pairs of vertices, the vertices should not be nil and not be equal.
+// Whether a cyclic graph will come into being is validated when committing.
+func (tx *Tx[V, W]) AddEdge(start, end V, weight W) error {
+	if isNilVertice(start) || start.ID() == "" {
+		return &ErrInvalidVertice{Vertice: start}
+	}
+	if isNilVertice(end) || end.ID() == "" {
+		return &ErrInvalidVertice{Vertice: end}
+	}
+	if verticeUID(start) == verticeUID(end) {
+		return &ErrSelfLoop{Vertice: start}
+	}
+	tx.ops = append(tx.ops, txOp[V, W]{kind: txAddEdge, start: start, end: end, weight: weight})
+	return nil
+}
+
+// RemoveEdge stages removing the direct connecting in the vertices pair.
+func (tx *Tx[V, W]) RemoveEdge(start, end V) {
+	if isNilVertice(start) || isNilVertice(end) {
+		return
+	}
+	tx.ops = append(tx.ops, txOp[V, W]{kind: txRemoveEdge, start: start, end: end})
+}
+
+// RemoveVertice stages removing the vertice and all the connecting of it.
+func (tx *Tx[V, W]) RemoveVertice(v V) {
+	if isNilVertice(v) {
+		return
+	}
+	tx.ops = append(tx.ops, txOp[V, W]{kind: txRemoveVertice, start: v})
+}
+
+// Rollback discards the staged mutations.
+func (tx *Tx[V, W]) Rollback() {
+	tx.ops = nil
+}
+
+// Commit applies the staged mutations to the DAG in order and clears them.
+// It returns ErrCycle if cyclic graph will come into being, or ErrDuplicateVertice if adding an existing vertice,
+// the DAG is unchanged if error returned.
+func (tx *Tx[V, W]) Commit() error {
+	ops := tx.ops
+	tx.ops = nil
+
+	d := tx.d
+	d.lock()
+	defer d.unlock()
+
+	// the blocks are cloned before their first mutation, so the DAG is unchanged if error returned.
+	nd := &Graph[V, W]{blocks: make(map[string]*block[V, W], len(d.blocks))}
+	for k, b := range d.blocks {
+		nd.blocks[k] = b
+	}
+	cloned := make(map[string]bool)
+	mutable := func(v V) *block[V, W] {
+		k := verticeUID(v)
+		b, ok := nd.blocks[k]
+		if !ok {
+			b = &block[V, W]{
+				vertice: v,
+				prev:    make(map[string]W),
+				next:    make(map[string]W),
+			}
+			nd.blocks[k] = b
+			cloned[k] = true
+		} else if !cloned[k] {
+			b = b.clone()
+			nd.blocks[k] = b
+			cloned[k] = true
+		}
+		return b
+	}
+	for _, op := range ops {
+		switch op.kind {
+		case txAddVertice:
+			if _, ok := nd.blocks[verticeUID(op.start)]; ok {
+				return &ErrDuplicateVertice{Vertice: op.start}
+			}
+			mutable(op.start)
+		case txAddEdge:
+			startID := verticeUID(op.start)
+			endID := verticeUID(op.end)
+			mutable(op.start).next[endID] = op.weight
+			mutable(op.end).prev[startID] = op.weight
+		case txRemoveEdge:
+			startID := verticeUID(op.start)
+			endID := verticeUID(op.end)
+			if startBlock, ok := nd.blocks[startID]; ok {
+				if _, ok := startBlock.next[endID]; ok {
+					delete(mutable(op.start).next, endID)
+					delete(mutable(op.end).prev, startID)
+				}
+			}
+		case txRemoveVertice:
+			b, ok := nd.blocks[verticeUID(op.start)]
+			if !ok {
+				continue
+			}
+			for kk := range b.prev {
+				mutable(nd.blocks[kk].vertice)
+			}
+			for kk := range b.next {
+				mutable(nd.blocks[kk].vertice)
+			}
+			nd.removeBlock(verticeUID(op.start))
+		}
+	}
+
+	if cycle := nd.cycleKeys(); cycle != nil {
+		return nd.cycleError(cycle)
+	}
+	d.setBlocks(nd.blocks)
+	return nil
+}
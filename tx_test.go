@@ -0,0 +1,97 @@
+package daggo_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	daggo "github.com/open-trust/dag-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTx(t *testing.T) {
+	t.Run("DAG.Begin", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		tx := d.Begin()
+		assert.True(errors.Is(tx.AddEdge(nil, V("a"), 0), &daggo.ErrInvalidVertice{}))
+		assert.True(errors.Is(tx.AddEdge(V("a"), V("a"), 0), &daggo.ErrSelfLoop{}))
+		assert.True(errors.Is(tx.AddVertice(V("")), &daggo.ErrInvalidVertice{}))
+		assert.Equal(0, tx.Len())
+
+		for i := 0; i < 10000; i++ {
+			assert.Nil(tx.AddEdge(V(fmt.Sprintf("v%d", i)), V(fmt.Sprintf("v%d", i+1)), 1))
+		}
+		assert.Nil(tx.AddVertice(V("x")))
+		assert.Equal(10001, tx.Len())
+		assert.Equal(0, d.Len())
+		assert.Nil(tx.Commit())
+		assert.Equal(0, tx.Len())
+		assert.Equal(10002, d.Len())
+		assert.Equal(daggo.Vertices{V("v0"), V("x")}, d.StartingVertices().Sort())
+
+		tx = d.Begin()
+		tx.RemoveVertice(V("v5000"))
+		tx.RemoveEdge(V("v0"), V("v1"))
+		assert.Nil(tx.AddEdge(V("v10000"), V("v0"), 1))
+		tx.Rollback()
+		assert.Nil(tx.Commit())
+		assert.Equal(10002, d.Len())
+	})
+
+	t.Run("Tx.Commit should validate once", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		assert.Nil(d.AddEdge(V("a"), V("b"), 1))
+		assert.Nil(d.AddEdge(V("b"), V("c"), 1))
+
+		tx := d.Begin()
+		// a cyclic graph in the middle of the batch is fine.
+		assert.Nil(tx.AddEdge(V("c"), V("a"), 1))
+		tx.RemoveEdge(V("a"), V("b"))
+		tx.RemoveVertice(V("b"))
+		assert.Nil(tx.AddEdge(V("c"), V("d"), 1))
+		assert.Nil(tx.Commit())
+
+		x := daggo.New()
+		assert.Nil(x.AddEdge(V("c"), V("a"), 1))
+		assert.Nil(x.AddEdge(V("c"), V("d"), 1))
+		assert.True(x.Equal(d))
+	})
+
+	t.Run("Tx.Commit should roll back", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := daggo.New()
+		assert.Nil(d.AddEdge(V("a"), V("b"), 1))
+		assert.Nil(d.AddEdge(V("b"), V("c"), 1))
+		x := d.Clone()
+
+		tx := d.Begin()
+		assert.Nil(tx.AddEdge(V("c"), V("x"), 1))
+		assert.Nil(tx.AddEdge(V("x"), V("a"), 1))
+		tx.RemoveVertice(V("y"))
+		err := tx.Commit()
+		var ce *daggo.ErrCycle
+		assert.True(errors.As(err, &ce))
+		assert.Equal([]string{"a", "b", "c", "x", "a"}, ce.Path.IDs())
+		assert.True(x.Equal(d))
+
+		tx = d.Begin()
+		assert.Nil(tx.AddEdge(V("c"), V("x"), 1))
+		assert.Nil(tx.AddVertice(V("a")))
+		assert.True(errors.Is(tx.Commit(), &daggo.ErrDuplicateVertice{}))
+		assert.True(x.Equal(d))
+
+		tx = d.Begin()
+		tx.RemoveEdge(V("a"), V("b"))
+		tx.RemoveVertice(V("c"))
+		assert.Nil(tx.AddEdge(V("b"), V("a"), 1))
+		assert.Nil(tx.AddEdge(V("a"), V("b"), 2))
+		assert.True(errors.As(tx.Commit(), &ce))
+		assert.Equal([]string{"a", "b", "a"}, ce.Path.IDs())
+		assert.True(x.Equal(d))
+	})
+}